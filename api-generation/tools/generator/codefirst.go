@@ -0,0 +1,768 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+const daprActorImportPath = "github.com/dapr/go-sdk/actor"
+
+// runSpecCommand implements `generator spec`: it derives an OpenAPI document from the actor
+// implementations in a Go package, following the path and tag conventions of multi-actors.yaml.
+func runSpecCommand(args []string) error {
+	flags := flag.NewFlagSet("spec", flag.ExitOnError)
+	title := flags.String("title", "", "API title (defaults to \"<package> API\")")
+	version := flags.String("version", "1.0.0", "API version")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: generator spec [--title <title>] [--version <version>] <go-package-dir> [output-file]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	pkg, err := parseGoPackage(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	doc, err := buildSpecFromPackage(pkg, *title, *version)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to marshal OpenAPI document: %v", err)
+	}
+	data := buf.Bytes()
+
+	if flags.NArg() < 2 {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(flags.Arg(1), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", flags.Arg(1), err)
+	}
+	fmt.Printf("Generated OpenAPI spec: %s\n", flags.Arg(1))
+	return nil
+}
+
+// goPackage is the syntax of a single Go package, indexed for spec generation.
+type goPackage struct {
+	name       string
+	files      []*ast.File
+	imports    map[*ast.File]map[string]string // local name -> import path, per file
+	typeSpecs  map[string]*ast.TypeSpec
+	typeDocs   map[string]string
+	typeFiles  map[string]*ast.File
+	consts     map[string]string        // string constant name -> value
+	enumValues map[string][]interface{} // named type -> values of its typed constants
+	methods    map[string][]*ast.FuncDecl
+	methodDocs map[string]string // method name -> doc comment from an interface declaring it
+	generated  map[*ast.File]bool
+}
+
+// parseGoPackage parses the non-test Go files in dir.
+func parseGoPackage(dir string) (*goPackage, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	pkg := &goPackage{
+		imports:    make(map[*ast.File]map[string]string),
+		typeSpecs:  make(map[string]*ast.TypeSpec),
+		typeDocs:   make(map[string]string),
+		typeFiles:  make(map[string]*ast.File),
+		consts:     make(map[string]string),
+		enumValues: make(map[string][]interface{}),
+		methods:    make(map[string][]*ast.FuncDecl),
+		methodDocs: make(map[string]string),
+		generated:  make(map[*ast.File]bool),
+	}
+
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		if pkg.name == "" {
+			pkg.name = file.Name.Name
+		}
+		pkg.files = append(pkg.files, file)
+		pkg.index(file)
+	}
+
+	if len(pkg.files) == 0 {
+		return nil, fmt.Errorf("no Go files found in %s", dir)
+	}
+	return pkg, nil
+}
+
+// index records the declarations of file.
+func (p *goPackage) index(file *ast.File) {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+	p.imports[file] = imports
+	p.generated[file] = isGeneratedFile(file)
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			switch decl.Tok {
+			case token.TYPE:
+				for _, spec := range decl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					doc := typeSpec.Doc
+					if doc == nil {
+						doc = decl.Doc
+					}
+					p.typeSpecs[typeSpec.Name.Name] = typeSpec
+					p.typeDocs[typeSpec.Name.Name] = docDescription(doc, typeSpec.Name.Name)
+					p.typeFiles[typeSpec.Name.Name] = file
+
+					if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+						for _, method := range iface.Methods.List {
+							for _, name := range method.Names {
+								if method.Doc != nil && p.methodDocs[name.Name] == "" {
+									p.methodDocs[name.Name] = strings.TrimSpace(method.Doc.Text())
+								}
+							}
+						}
+					}
+				}
+			case token.CONST:
+				p.indexConsts(decl)
+			}
+		case *ast.FuncDecl:
+			if recv := receiverTypeName(decl); recv != "" {
+				p.methods[recv] = append(p.methods[recv], decl)
+			}
+		}
+	}
+}
+
+// indexConsts records string constants and the values of typed constants (enum values).
+func (p *goPackage) indexConsts(decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		valueSpec := spec.(*ast.ValueSpec)
+		for i, name := range valueSpec.Names {
+			if i >= len(valueSpec.Values) {
+				continue
+			}
+			value, ok := literalValue(valueSpec.Values[i])
+			if !ok {
+				continue
+			}
+			if s, ok := value.(string); ok {
+				p.consts[name.Name] = s
+			}
+			if typeName, ok := valueSpec.Type.(*ast.Ident); ok {
+				p.enumValues[typeName.Name] = append(p.enumValues[typeName.Name], value)
+			}
+		}
+	}
+}
+
+// actorImpl is an actor implementation found in a package.
+type actorImpl struct {
+	typeName  string
+	actorType string
+	methods   []*ast.FuncDecl
+}
+
+// findActors returns the hand-written types that implement actor.ServerContext: structs embedding
+// the Dapr actor base type and declaring Type() string. Generated files (wrappers, mocks) are skipped.
+func (p *goPackage) findActors() []actorImpl {
+	var actors []actorImpl
+	for _, name := range sortedKeys(p.typeSpecs) {
+		file := p.typeFiles[name]
+		if p.generated[file] || !p.embedsActorBase(name) {
+			continue
+		}
+
+		actor := actorImpl{typeName: name}
+		for _, method := range p.methods[name] {
+			if method.Name.Name == "Type" {
+				actor.actorType = p.returnedString(method)
+			}
+			if p.isActorMethod(method) {
+				actor.methods = append(actor.methods, method)
+			}
+		}
+		if actor.actorType == "" {
+			continue // Does not implement Type() string
+		}
+
+		sort.Slice(actor.methods, func(i, j int) bool {
+			return actor.methods[i].Name.Name < actor.methods[j].Name.Name
+		})
+		actors = append(actors, actor)
+	}
+	return actors
+}
+
+// embedsActorBase reports whether the struct type name embeds actor.ServerImplBaseCtx (or ServerImplBase).
+func (p *goPackage) embedsActorBase(name string) bool {
+	structType, ok := p.typeSpecs[name].Type.(*ast.StructType)
+	if !ok {
+		return false
+	}
+	for _, field := range structType.Fields.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+		typeExpr := field.Type
+		if star, ok := typeExpr.(*ast.StarExpr); ok {
+			typeExpr = star.X
+		}
+		pkgName, typeName := p.selector(p.typeFiles[name], typeExpr)
+		if pkgName == daprActorImportPath && (typeName == "ServerImplBaseCtx" || typeName == "ServerImplBase") {
+			return true
+		}
+	}
+	return false
+}
+
+// returnedString resolves the value returned by a Type() method: a string literal or constant.
+// It falls back to the receiver type name when the value cannot be resolved statically.
+func (p *goPackage) returnedString(method *ast.FuncDecl) string {
+	if method.Type.Results == nil || len(method.Type.Results.List) != 1 || !isIdent(method.Type.Results.List[0].Type, "string") {
+		return ""
+	}
+	if method.Body != nil && len(method.Body.List) == 1 {
+		if ret, ok := method.Body.List[0].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			switch value := ret.Results[0].(type) {
+			case *ast.BasicLit:
+				if s, err := strconv.Unquote(value.Value); err == nil {
+					return s
+				}
+			case *ast.Ident:
+				if s, ok := p.consts[value.Name]; ok {
+					return s
+				}
+			}
+		}
+	}
+	return receiverTypeName(method)
+}
+
+// isActorMethod reports whether method has the actor method shape
+// func (ctx context.Context[, request T]) (*R, error).
+func (p *goPackage) isActorMethod(method *ast.FuncDecl) bool {
+	if !method.Name.IsExported() {
+		return false
+	}
+	file := p.fileOf(method)
+
+	params := fieldTypes(method.Type.Params)
+	if len(params) < 1 || len(params) > 2 {
+		return false
+	}
+	if pkgName, typeName := p.selector(file, params[0]); pkgName != "context" || typeName != "Context" {
+		return false
+	}
+
+	results := fieldTypes(method.Type.Results)
+	if len(results) != 2 || !isIdent(results[1], "error") {
+		return false
+	}
+	_, isPointer := results[0].(*ast.StarExpr)
+	return isPointer
+}
+
+// buildSpecFromPackage creates the OpenAPI document describing the actors in pkg.
+func buildSpecFromPackage(pkg *goPackage, title, version string) (*openapi3.T, error) {
+	actors := pkg.findActors()
+	if len(actors) == 0 {
+		return nil, fmt.Errorf("no actor implementations found in package %s", pkg.name)
+	}
+
+	if title == "" {
+		title = pkg.name + " API"
+	}
+
+	actorID := openapi3.NewPathParameter("actorId").
+		WithDescription("Unique identifier for the actor instance").
+		WithSchema(openapi3.NewStringSchema())
+
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       title,
+			Description: "Generated from the Go actor implementations in package " + pkg.name + ".",
+			Version:     version,
+		},
+		Paths: openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Parameters: openapi3.ParametersMap{
+				"ActorId": &openapi3.ParameterRef{Value: actorID},
+			},
+			Schemas: openapi3.Schemas{},
+		},
+	}
+
+	schemas := &schemaBuilder{pkg: pkg, components: doc.Components.Schemas}
+	for _, actor := range actors {
+		for _, method := range actor.methods {
+			op, err := buildOperation(pkg, schemas, actor, method, actorID)
+			if err != nil {
+				return nil, err
+			}
+
+			httpMethod := "POST"
+			if op.RequestBody == nil && strings.HasPrefix(method.Name.Name, "Get") {
+				httpMethod = "GET"
+			}
+			path := fmt.Sprintf("/%s/{actorId}/method/%s", actor.actorType, lowerFirst(method.Name.Name))
+			doc.AddOperation(path, httpMethod, op)
+		}
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("derived OpenAPI document is invalid: %v", err)
+	}
+	return doc, nil
+}
+
+// buildOperation describes an actor method as an OpenAPI operation.
+func buildOperation(pkg *goPackage, schemas *schemaBuilder, actor actorImpl, method *ast.FuncDecl, actorID *openapi3.Parameter) (*openapi3.Operation, error) {
+	file := pkg.fileOf(method)
+	name := method.Name.Name
+
+	doc := strings.TrimSpace(method.Doc.Text())
+	if doc == "" {
+		doc = pkg.methodDocs[name]
+	}
+	summary := name
+	if doc != "" {
+		// The first sentence of the doc comment, like the summaries in multi-actors.yaml
+		summary = strings.Join(strings.Fields(doc), " ")
+		if i := strings.Index(summary, ". "); i >= 0 {
+			summary = summary[:i]
+		}
+		summary = strings.TrimSuffix(summary, ".")
+	}
+
+	op := &openapi3.Operation{
+		Summary:     summary,
+		Tags:        []string{"ActorType:" + actor.actorType},
+		OperationID: lowerFirst(actor.actorType) + name,
+		Parameters: openapi3.Parameters{
+			&openapi3.ParameterRef{Ref: "#/components/parameters/ActorId", Value: actorID},
+		},
+		Responses: openapi3.NewResponses(),
+	}
+	if doc != "" && strings.Join(strings.Fields(doc), " ") != summary {
+		op.Description = doc
+	}
+
+	params := fieldTypes(method.Type.Params)
+	if len(params) == 2 {
+		requestSchema, err := schemas.schemaFor(file, params[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s request: %v", actor.typeName, name, err)
+		}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().
+			WithRequired(true).
+			WithJSONSchemaRef(requestSchema)}
+	}
+
+	results := fieldTypes(method.Type.Results)
+	responseSchema, err := schemas.schemaFor(file, results[0].(*ast.StarExpr).X)
+	if err != nil {
+		return nil, fmt.Errorf("%s.%s response: %v", actor.typeName, name, err)
+	}
+	op.Responses = openapi3.NewResponses(openapi3.WithStatus(200, &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription(name + " succeeded").
+			WithJSONSchemaRef(responseSchema),
+	}))
+
+	return op, nil
+}
+
+// schemaBuilder converts Go type expressions to schemas, adding referenced package types as components.
+type schemaBuilder struct {
+	pkg        *goPackage
+	components openapi3.Schemas
+}
+
+// schemaFor returns the schema for a type expression appearing in file.
+func (b *schemaBuilder) schemaFor(file *ast.File, expr ast.Expr) (*openapi3.SchemaRef, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if schema := builtinSchema(expr.Name); schema != nil {
+			return schema.NewRef(), nil
+		}
+		if _, ok := b.pkg.typeSpecs[expr.Name]; ok {
+			if err := b.addComponent(expr.Name); err != nil {
+				return nil, err
+			}
+			return openapi3.NewSchemaRef("#/components/schemas/"+expr.Name, b.components[expr.Name].Value), nil
+		}
+		return nil, fmt.Errorf("unsupported type %s", expr.Name)
+	case *ast.StarExpr:
+		return b.schemaFor(file, expr.X)
+	case *ast.ArrayType:
+		if isIdent(expr.Elt, "byte") {
+			return openapi3.NewBytesSchema().NewRef(), nil
+		}
+		items, err := b.schemaFor(file, expr.Elt)
+		if err != nil {
+			return nil, err
+		}
+		schema := openapi3.NewArraySchema()
+		schema.Items = items
+		return schema.NewRef(), nil
+	case *ast.MapType:
+		if !isIdent(expr.Key, "string") {
+			return nil, fmt.Errorf("map keys must be strings")
+		}
+		values, err := b.schemaFor(file, expr.Value)
+		if err != nil {
+			return nil, err
+		}
+		schema := openapi3.NewObjectSchema()
+		if values.Ref == "" && values.Value != nil && values.Value.IsEmpty() {
+			schema.AdditionalProperties = openapi3.AdditionalProperties{Has: openapi3.BoolPtr(true)}
+		} else {
+			schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: values}
+		}
+		return schema.NewRef(), nil
+	case *ast.SelectorExpr:
+		pkgPath, typeName := b.pkg.selector(file, expr)
+		if pkgPath == "time" && typeName == "Time" {
+			return openapi3.NewDateTimeSchema().NewRef(), nil
+		}
+		if pkgPath == "github.com/google/uuid" && typeName == "UUID" {
+			return openapi3.NewUUIDSchema().NewRef(), nil
+		}
+		return openapi3.NewSchema().NewRef(), nil // Foreign types are left unconstrained
+	case *ast.InterfaceType:
+		return openapi3.NewSchema().NewRef(), nil
+	}
+	return nil, fmt.Errorf("unsupported type expression %T", expr)
+}
+
+// addComponent adds the schema of the package type name to the components, once.
+func (b *schemaBuilder) addComponent(name string) error {
+	if _, ok := b.components[name]; ok {
+		return nil
+	}
+
+	// Reserve the name first so recursive types terminate
+	schema := openapi3.NewSchema()
+	b.components[name] = schema.NewRef()
+
+	typeSpec := b.pkg.typeSpecs[name]
+	file := b.pkg.typeFiles[name]
+
+	switch typeExpr := typeSpec.Type.(type) {
+	case *ast.InterfaceType:
+		// A sealed interface (unexported marker method) is a oneOf of the types implementing it
+		for _, variant := range b.pkg.sealedVariants(typeExpr) {
+			ref, err := b.schemaFor(file, ast.NewIdent(variant))
+			if err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+			schema.OneOf = append(schema.OneOf, ref)
+		}
+	case *ast.StructType:
+		schema.Type = &openapi3.Types{openapi3.TypeObject}
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Has: openapi3.BoolPtr(false)}
+		if err := b.addProperties(schema, file, typeExpr); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	default:
+		resolved, err := b.schemaFor(file, typeExpr)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		if resolved.Ref != "" {
+			schema.AllOf = openapi3.SchemaRefs{resolved}
+		} else {
+			*schema = *resolved.Value
+		}
+		// Typed constants of a named basic type enumerate its allowed values
+		if typeSpec.Assign == 0 && len(b.pkg.enumValues[name]) > 0 {
+			schema.Enum = b.pkg.enumValues[name]
+		}
+	}
+
+	schema.Description = b.pkg.typeDocs[name]
+	return nil
+}
+
+// addProperties adds the exported fields of a struct to schema, honouring json tags.
+// Embedded package structs are flattened, as encoding/json does.
+func (b *schemaBuilder) addProperties(schema *openapi3.Schema, file *ast.File, structType *ast.StructType) error {
+	for _, field := range structType.Fields.List {
+		jsonName, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		if len(field.Names) == 0 {
+			embedded := field.Type
+			if star, ok := embedded.(*ast.StarExpr); ok {
+				embedded = star.X
+			}
+			if ident, ok := embedded.(*ast.Ident); ok && jsonName == "" {
+				if inner, ok := b.pkg.typeSpecs[ident.Name].Type.(*ast.StructType); ok {
+					if err := b.addProperties(schema, b.pkg.typeFiles[ident.Name], inner); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+
+		for _, fieldName := range field.Names {
+			if !fieldName.IsExported() {
+				continue
+			}
+			name := jsonName
+			if name == "" {
+				name = fieldName.Name
+			}
+
+			propRef, err := b.schemaFor(file, field.Type)
+			if err != nil {
+				return fmt.Errorf("field %s: %v", fieldName.Name, err)
+			}
+			description := strings.TrimSpace(field.Doc.Text())
+			if description == "" {
+				description = strings.TrimSpace(field.Comment.Text())
+			}
+			if description != "" && propRef.Ref == "" {
+				propRef.Value.Description = description
+			}
+
+			schema.WithPropertyRef(name, propRef)
+			if !omitEmpty {
+				schema.Required = append(schema.Required, name)
+				// A pointer that is always sent can only be missing as null
+				if _, isPointer := field.Type.(*ast.StarExpr); isPointer && propRef.Ref == "" {
+					propRef.Value.Nullable = true
+				}
+			}
+		}
+	}
+	sort.Strings(schema.Required)
+	return nil
+}
+
+// builtinSchema maps predeclared Go types to schemas; it returns nil for other identifiers.
+func builtinSchema(name string) *openapi3.Schema {
+	switch name {
+	case "string":
+		return openapi3.NewStringSchema()
+	case "bool":
+		return openapi3.NewBoolSchema()
+	case "int32":
+		return openapi3.NewInt32Schema()
+	case "int64":
+		return openapi3.NewInt64Schema()
+	case "int", "int8", "int16", "uint", "uint8", "uint16", "uint32", "uint64":
+		return openapi3.NewIntegerSchema()
+	case "float32":
+		return openapi3.NewFloat64Schema().WithFormat("float")
+	case "float64":
+		return openapi3.NewFloat64Schema().WithFormat("double")
+	case "any":
+		return openapi3.NewSchema()
+	}
+	return nil
+}
+
+// jsonFieldName reads the encoding/json name and omitempty option of a struct field.
+func jsonFieldName(field *ast.Field) (name string, omitEmpty, skip bool) {
+	if field.Tag == nil {
+		return "", false, false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false, false
+	}
+	value, ok := reflect.StructTag(tag).Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+	if value == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(value, ",")
+	return parts[0], contains(parts[1:], "omitempty"), false
+}
+
+// docDescription turns a Go doc comment into a schema description, dropping the leading
+// identifier that Go doc comments conventionally start with.
+func docDescription(doc *ast.CommentGroup, name string) string {
+	text := strings.TrimSpace(doc.Text())
+	if rest, ok := strings.CutPrefix(text, name+" "); ok {
+		text = capitalizeFirst(rest)
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// isGeneratedFile reports whether file carries a "Code generated ... DO NOT EDIT" comment.
+func isGeneratedFile(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		text := group.Text()
+		if strings.Contains(text, "Code generated") && strings.Contains(text, "DO NOT EDIT") {
+			return true
+		}
+	}
+	return false
+}
+
+// sealedVariants returns the package types declaring the unexported marker method of a sealed interface.
+func (p *goPackage) sealedVariants(iface *ast.InterfaceType) []string {
+	var variants []string
+	for _, method := range iface.Methods.List {
+		for _, name := range method.Names {
+			if name.IsExported() {
+				continue
+			}
+			for _, typeName := range sortedKeys(p.methods) {
+				for _, decl := range p.methods[typeName] {
+					if decl.Name.Name == name.Name {
+						variants = append(variants, typeName)
+					}
+				}
+			}
+		}
+	}
+	return variants
+}
+
+// selector resolves pkg.Name type expressions in file to (import path, name).
+func (p *goPackage) selector(file *ast.File, expr ast.Expr) (string, string) {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return "", ""
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+	return p.imports[file][ident.Name], sel.Sel.Name
+}
+
+// fileOf returns the file declaring node.
+func (p *goPackage) fileOf(node ast.Node) *ast.File {
+	for _, file := range p.files {
+		if file.Pos() <= node.Pos() && node.End() <= file.End() {
+			return file
+		}
+	}
+	return nil
+}
+
+// receiverTypeName returns the receiver type name of a method, or "" for plain functions.
+func receiverTypeName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	typeExpr := decl.Recv.List[0].Type
+	if star, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = star.X
+	}
+	if ident, ok := typeExpr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// fieldTypes flattens a field list into one type expression per value.
+func fieldTypes(fields *ast.FieldList) []ast.Expr {
+	if fields == nil {
+		return nil
+	}
+	var types []ast.Expr
+	for _, field := range fields.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			types = append(types, field.Type)
+		}
+	}
+	return types
+}
+
+// literalValue evaluates a basic literal (optionally negated) to a Go value.
+func literalValue(expr ast.Expr) (interface{}, bool) {
+	negate := false
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.SUB {
+		negate = true
+		expr = unary.X
+	}
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return nil, false
+	}
+	switch lit.Kind {
+	case token.STRING:
+		if negate {
+			return nil, false
+		}
+		s, err := strconv.Unquote(lit.Value)
+		return s, err == nil
+	case token.INT:
+		n, err := strconv.ParseInt(lit.Value, 0, 64)
+		if negate {
+			n = -n
+		}
+		return n, err == nil
+	case token.FLOAT:
+		f, err := strconv.ParseFloat(lit.Value, 64)
+		if negate {
+			f = -f
+		}
+		return f, err == nil
+	}
+	return nil, false
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// shopActorSource is an actor package exercising the Go constructs the spec command maps.
+const shopActorSource = `package shop
+
+import (
+	"context"
+	"time"
+
+	"github.com/dapr/go-sdk/actor"
+)
+
+const actorType = "ShopActor"
+
+// Status Lifecycle state of an order
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+)
+
+// Order is an order placed in the shop.
+type Order struct {
+	// Identifier of the order
+	ID       string    ` + "`json:\"id\"`" + `
+	Quantity int32     ` + "`json:\"quantity\"`" + `
+	Note     *string   ` + "`json:\"note\"`" + `
+	Coupon   string    ` + "`json:\"coupon,omitempty\"`" + `
+	Status   Status    ` + "`json:\"status\"`" + `
+	Placed   time.Time ` + "`json:\"placed\"`" + `
+	Lines    []Line    ` + "`json:\"lines\"`" + `
+	Secret   string    ` + "`json:\"-\"`" + `
+	internal string
+}
+
+type Line struct {
+	SKU string ` + "`json:\"sku\"`" + `
+}
+
+// Payment is a sealed union of payment methods.
+type Payment interface {
+	isPayment()
+}
+
+type Card struct {
+	Number string ` + "`json:\"number\"`" + `
+}
+
+type Voucher struct {
+	Code string ` + "`json:\"code\"`" + `
+}
+
+func (Card) isPayment()    {}
+func (Voucher) isPayment() {}
+
+type PayRequest struct {
+	Payment Payment ` + "`json:\"payment\"`" + `
+}
+
+type ShopActor struct {
+	actor.ServerImplBaseCtx
+}
+
+func (a *ShopActor) Type() string {
+	return actorType
+}
+
+// PlaceOrder places an order. The order is validated first.
+func (a *ShopActor) PlaceOrder(ctx context.Context, order Order) (*Order, error) {
+	return &order, nil
+}
+
+// GetOrder returns the current order.
+func (a *ShopActor) GetOrder(ctx context.Context) (*Order, error) {
+	return nil, nil
+}
+
+func (a *ShopActor) Pay(ctx context.Context, request PayRequest) (*Order, error) {
+	return nil, nil
+}
+
+// Not actor methods: unexported, or not returning (*T, error)
+func (a *ShopActor) audit(ctx context.Context) (*Order, error) { return nil, nil }
+func (a *ShopActor) Close(ctx context.Context) error           { return nil }
+`
+
+// shopGeneratedSource is a generated wrapper that must not be mistaken for an actor implementation.
+const shopGeneratedSource = `// Code generated from OpenAPI specification. DO NOT EDIT manually.
+package shop
+
+import (
+	"context"
+
+	"github.com/dapr/go-sdk/actor"
+)
+
+type ShopActorMock struct {
+	actor.ServerImplBaseCtx
+}
+
+func (m *ShopActorMock) Type() string {
+	return "ShopActorMock"
+}
+
+func (m *ShopActorMock) GetOrder(ctx context.Context) (*Order, error) {
+	return nil, nil
+}
+`
+
+// buildShopSpec derives the spec of the shop package.
+func buildShopSpec(t *testing.T) *openapi3.T {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{"shop.go": shopActorSource, "mock.go": shopGeneratedSource}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pkg, err := parseGoPackage(dir)
+	if err != nil {
+		t.Fatalf("parseGoPackage: %v", err)
+	}
+	doc, err := buildSpecFromPackage(pkg, "", "1.0.0")
+	if err != nil {
+		t.Fatalf("buildSpecFromPackage: %v", err)
+	}
+	return doc
+}
+
+func TestSpecOperations(t *testing.T) {
+	doc := buildShopSpec(t)
+
+	tests := []struct {
+		path       string
+		httpMethod string
+		summary    string
+		request    string // Component name of the request body; "" for none
+		response   string
+	}{
+		{"/ShopActor/{actorId}/method/getOrder", "GET", "GetOrder returns the current order", "", "Order"},
+		{"/ShopActor/{actorId}/method/pay", "POST", "Pay", "PayRequest", "Order"},
+		{"/ShopActor/{actorId}/method/placeOrder", "POST", "PlaceOrder places an order", "Order", "Order"},
+	}
+
+	if got := len(doc.Paths.Map()); got != len(tests) {
+		t.Fatalf("got %d paths, want %d: %v", got, len(tests), sortedKeys(doc.Paths.Map()))
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			pathItem := doc.Paths.Value(tt.path)
+			if pathItem == nil {
+				t.Fatalf("path not generated")
+			}
+			op := pathItem.GetOperation(tt.httpMethod)
+			if op == nil {
+				t.Fatalf("no %s operation", tt.httpMethod)
+			}
+			if op.Summary != tt.summary {
+				t.Errorf("summary = %q, want %q", op.Summary, tt.summary)
+			}
+			if !reflect.DeepEqual(op.Tags, []string{"ActorType:ShopActor"}) {
+				t.Errorf("tags = %v", op.Tags)
+			}
+
+			request := ""
+			if schema := requestSchema(op); schema != nil {
+				request = refName(schema.Ref)
+			}
+			if request != tt.request {
+				t.Errorf("request = %q, want %q", request, tt.request)
+			}
+			if response := responseSchema(op); response == nil || refName(response.Ref) != tt.response {
+				t.Errorf("response = %v, want %s", response, tt.response)
+			}
+		})
+	}
+}
+
+func TestSpecSchemas(t *testing.T) {
+	schemas := buildShopSpec(t).Components.Schemas
+	order := schemas["Order"].Value
+
+	t.Run("required", func(t *testing.T) {
+		want := []string{"id", "lines", "note", "placed", "quantity", "status"}
+		if !reflect.DeepEqual(order.Required, want) {
+			t.Errorf("required = %v, want %v", order.Required, want)
+		}
+	})
+
+	t.Run("skipped fields", func(t *testing.T) {
+		for _, name := range []string{"Secret", "internal", "-"} {
+			if _, ok := order.Properties[name]; ok {
+				t.Errorf("property %s generated", name)
+			}
+		}
+		if len(order.Properties) != 7 {
+			t.Errorf("got properties %v", sortedKeys(order.Properties))
+		}
+	})
+
+	properties := []struct {
+		name        string
+		typ         string
+		format      string
+		ref         string
+		nullable    bool
+		description string
+	}{
+		{name: "id", typ: "string", description: "Identifier of the order"},
+		{name: "quantity", typ: "integer", format: "int32"},
+		{name: "note", typ: "string", nullable: true},
+		{name: "coupon", typ: "string"},
+		{name: "status", ref: "Status"},
+		{name: "placed", typ: "string", format: "date-time"},
+		{name: "lines", typ: "array"},
+	}
+	for _, tt := range properties {
+		t.Run("property "+tt.name, func(t *testing.T) {
+			prop := order.Properties[tt.name]
+			if prop == nil {
+				t.Fatal("property not generated")
+			}
+			if refName(prop.Ref) != tt.ref {
+				t.Errorf("ref = %q, want %q", prop.Ref, tt.ref)
+			}
+			if tt.ref != "" {
+				return
+			}
+			if !prop.Value.Type.Is(tt.typ) {
+				t.Errorf("type = %v, want %s", prop.Value.Type, tt.typ)
+			}
+			if prop.Value.Format != tt.format {
+				t.Errorf("format = %q, want %q", prop.Value.Format, tt.format)
+			}
+			if prop.Value.Nullable != tt.nullable {
+				t.Errorf("nullable = %v, want %v", prop.Value.Nullable, tt.nullable)
+			}
+			if prop.Value.Description != tt.description {
+				t.Errorf("description = %q, want %q", prop.Value.Description, tt.description)
+			}
+		})
+	}
+
+	t.Run("array items", func(t *testing.T) {
+		if items := order.Properties["lines"].Value.Items; items == nil || refName(items.Ref) != "Line" {
+			t.Errorf("items = %v, want Line", items)
+		}
+	})
+
+	t.Run("enum", func(t *testing.T) {
+		status := schemas["Status"].Value
+		if !reflect.DeepEqual(status.Enum, []interface{}{"open", "closed"}) {
+			t.Errorf("enum = %v", status.Enum)
+		}
+		if status.Description != "Lifecycle state of an order" {
+			t.Errorf("description = %q", status.Description)
+		}
+	})
+
+	t.Run("sealed union", func(t *testing.T) {
+		payment := schemas["Payment"].Value
+		var variants []string
+		for _, variant := range payment.OneOf {
+			variants = append(variants, refName(variant.Ref))
+		}
+		if !reflect.DeepEqual(variants, []string{"Card", "Voucher"}) {
+			t.Errorf("oneOf = %v", variants)
+		}
+	})
+
+	t.Run("closed objects", func(t *testing.T) {
+		if !isClosedObject(order) {
+			t.Error("struct schemas must not allow additional properties")
+		}
+	})
+}
+
+func TestSpecWithoutActors(t *testing.T) {
+	dir := t.TempDir()
+	source := "package empty\n\ntype Value struct{}\n"
+	if err := os.WriteFile(filepath.Join(dir, "empty.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := parseGoPackage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buildSpecFromPackage(pkg, "", "1.0.0"); err == nil {
+		t.Fatal("expected an error for a package without actor implementations")
+	}
+}
@@ -0,0 +1,563 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Change is a difference between two versions of an actor schema.
+type Change struct {
+	ActorType string `json:"actorType"`
+	Method    string `json:"method,omitempty"`
+	Location  string `json:"location,omitempty"` // e.g. "request SetValueRequest.value"
+	Kind      string `json:"kind"`
+	Message   string `json:"message"`
+	Breaking  bool   `json:"breaking"`
+}
+
+// CompatReport is the machine-readable result of comparing two specs.
+type CompatReport struct {
+	Breaking bool     `json:"breaking"`
+	Changes  []Change `json:"changes"`
+}
+
+// direction tells whether a schema is sent to the actor or returned by it, which decides
+// whether a change breaks existing clients: requests may only get looser, responses only richer.
+type direction string
+
+const (
+	directionRequest  direction = "request"
+	directionResponse direction = "response"
+)
+
+// runCompatCommand implements `generator compat`: it reports the changes between two specs
+// per actor type and returns whether any of them is breaking.
+func runCompatCommand(args []string) (bool, error) {
+	flags := flag.NewFlagSet("compat", flag.ExitOnError)
+	format := flags.String("format", "text", "output format: text or json")
+	configPath := flags.String("config", "", "compare the specs of a config file with their copies under <old-config-dir>")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: generator compat [--format text|json] <old-openapi-file-or-dir> <new-openapi-file-or-dir>")
+		fmt.Fprintln(flags.Output(), "       generator compat [--format text|json] --config <config-file> <old-config-dir>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *configPath == "" && flags.NArg() != 2 || *configPath != "" && flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		return false, fmt.Errorf("unknown format %q", *format)
+	}
+
+	oldPaths, newPaths := []string{flags.Arg(0)}, []string{flags.Arg(1)}
+	if *configPath != "" {
+		config, err := loadConfig(*configPath)
+		if err != nil {
+			return false, err
+		}
+		oldPaths, newPaths = config.oldSpecPaths(flags.Arg(0)), config.specPaths()
+	}
+
+	docs := make([]*openapi3.T, 2)
+	for i, paths := range [][]string{oldPaths, newPaths} {
+		doc, err := loadSpecs(paths)
+		if err != nil {
+			return false, err
+		}
+		docs[i] = doc
+	}
+
+	report := compareSpecs(docs[0], docs[1])
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return report.Breaking, encoder.Encode(report)
+	}
+
+	for _, change := range report.Changes {
+		severity := "info    "
+		if change.Breaking {
+			severity = "BREAKING"
+		}
+		subject := change.ActorType
+		if change.Method != "" {
+			subject += "." + change.Method
+		}
+		if change.Location != "" {
+			subject += " " + change.Location
+		}
+		fmt.Printf("%s %s: %s\n", severity, subject, change.Message)
+	}
+	if report.Breaking {
+		fmt.Println("Breaking changes found")
+	} else {
+		fmt.Println("No breaking changes")
+	}
+	return report.Breaking, nil
+}
+
+// compareSpecs reports the changes between the actor operations of two specs.
+func compareSpecs(oldDoc, newDoc *openapi3.T) CompatReport {
+	c := &compatChecker{}
+
+	oldActors := getActorOperations(oldDoc)
+	newActors := getActorOperations(newDoc)
+
+	for _, actorType := range sortedKeys(oldActors) {
+		if _, ok := newActors[actorType]; !ok {
+			c.add(Change{ActorType: actorType, Kind: "actor-removed", Message: "actor type removed", Breaking: true})
+		}
+	}
+
+	for _, actorType := range sortedKeys(newActors) {
+		oldMethods, ok := oldActors[actorType]
+		if !ok {
+			c.add(Change{ActorType: actorType, Kind: "actor-added", Message: "actor type added"})
+			continue
+		}
+		newMethods := newActors[actorType]
+
+		c.compareActorID(actorType, getActorIDSchemaRef(oldDoc, actorType), getActorIDSchemaRef(newDoc, actorType))
+
+		for _, name := range sortedKeys(oldMethods) {
+			if _, ok := newMethods[name]; !ok {
+				c.add(Change{ActorType: actorType, Method: name, Kind: "method-removed", Message: "method removed", Breaking: true})
+			}
+		}
+		for _, name := range sortedKeys(newMethods) {
+			oldOp, ok := oldMethods[name]
+			if !ok {
+				c.add(Change{ActorType: actorType, Method: name, Kind: "method-added", Message: "method added"})
+				continue
+			}
+			c.compareMethod(actorType, name, oldOp, newMethods[name])
+		}
+	}
+
+	return CompatReport{Breaking: c.breaking, Changes: c.changes}
+}
+
+// getActorOperations groups operations by actor type and Go method name.
+func getActorOperations(doc *openapi3.T) map[string]map[string]*openapi3.Operation {
+	actors := make(map[string]map[string]*openapi3.Operation)
+	paths := doc.Paths.Map()
+	for _, path := range sortedKeys(paths) {
+		for _, httpMethod := range httpMethods {
+			op := paths[path].GetOperation(httpMethod)
+			if op == nil {
+				continue
+			}
+			actorType := getOperationActorType(op)
+			methodName := extractMethodNameFromPath(path)
+			if actorType == "" || methodName == "" {
+				continue
+			}
+			if actors[actorType] == nil {
+				actors[actorType] = make(map[string]*openapi3.Operation)
+			}
+			actors[actorType][capitalizeFirst(methodName)] = op
+		}
+	}
+	return actors
+}
+
+// getActorIDSchemaRef wraps the actorId parameter schema of an actor type for comparison.
+func getActorIDSchemaRef(doc *openapi3.T, actorType string) *openapi3.SchemaRef {
+	schema := getActorIDSchema(doc, actorType)
+	if schema == nil {
+		return nil
+	}
+	return schema.NewRef()
+}
+
+// compatChecker accumulates changes while walking two versions of the same operations.
+type compatChecker struct {
+	changes  []Change
+	breaking bool
+
+	// Scope of the schemas currently being compared
+	actorType string
+	method    string
+	visited   map[string]bool
+}
+
+func (c *compatChecker) add(change Change) {
+	c.changes = append(c.changes, change)
+	if change.Breaking {
+		c.breaking = true
+	}
+}
+
+// report records a change found at location within the current scope.
+func (c *compatChecker) report(location, kind, message string, breaking bool) {
+	c.add(Change{
+		ActorType: c.actorType,
+		Method:    c.method,
+		Location:  location,
+		Kind:      kind,
+		Message:   message,
+		Breaking:  breaking,
+	})
+}
+
+func (c *compatChecker) scope(actorType, method string) {
+	c.actorType = actorType
+	c.method = method
+	c.visited = make(map[string]bool)
+}
+
+// compareActorID compares the actorId parameter constraints; actor IDs are sent by clients.
+func (c *compatChecker) compareActorID(actorType string, oldRef, newRef *openapi3.SchemaRef) {
+	c.scope(actorType, "")
+	if oldRef != nil && newRef != nil {
+		c.compareSchema("actorId", oldRef, newRef, directionRequest)
+	}
+}
+
+// compareMethod compares the request and response bodies of one actor method.
+func (c *compatChecker) compareMethod(actorType, method string, oldOp, newOp *openapi3.Operation) {
+	c.scope(actorType, method)
+
+	oldRequest, newRequest := requestSchema(oldOp), requestSchema(newOp)
+	switch {
+	case oldRequest == nil && newRequest != nil:
+		c.report("request", "request-added", "request body added", true)
+	case oldRequest != nil && newRequest == nil:
+		c.report("request", "request-removed", "request body removed", true)
+	case oldRequest != nil:
+		c.compareSchema("request", oldRequest, newRequest, directionRequest)
+	}
+
+	oldResponse, newResponse := responseSchema(oldOp), responseSchema(newOp)
+	switch {
+	case oldResponse == nil && newResponse != nil:
+		c.report("response", "response-added", "response body added", false)
+	case oldResponse != nil && newResponse == nil:
+		c.report("response", "response-removed", "response body removed", true)
+	case oldResponse != nil:
+		c.compareSchema("response", oldResponse, newResponse, directionResponse)
+	}
+
+	c.compareErrors(oldOp, newOp)
+}
+
+// compareErrors compares the declared error responses of a method. Errors are returned to
+// clients, so a new error or a changed status or code breaks clients that handle them by type.
+func (c *compatChecker) compareErrors(oldOp, newOp *openapi3.Operation) {
+	oldErrors, newErrors := errorResponses(oldOp), errorResponses(newOp)
+
+	for _, name := range sortedKeys(oldErrors) {
+		if _, ok := newErrors[name]; !ok {
+			c.report("error "+name, "error-removed", fmt.Sprintf("error response %s (%d) removed", name, oldErrors[name].status), false)
+		}
+	}
+	for _, name := range sortedKeys(newErrors) {
+		newErr := newErrors[name]
+		oldErr, ok := oldErrors[name]
+		if !ok {
+			c.report("error "+name, "error-added", fmt.Sprintf("error response %s (%d) added", name, newErr.status), true)
+			continue
+		}
+		if oldErr.status != newErr.status {
+			c.report("error "+name, "error-status-changed", fmt.Sprintf("status changed from %d to %d", oldErr.status, newErr.status), true)
+		}
+		if oldCode, newCode := errorCode(name, oldErr.schema.Value), errorCode(name, newErr.schema.Value); oldCode != newCode {
+			c.report("error "+name, "error-code-changed", fmt.Sprintf("error code changed from %s to %s", oldCode, newCode), true)
+		}
+		c.compareSchema("error "+name, oldErr.schema, newErr.schema, directionResponse)
+	}
+}
+
+// errorResponse is a declared error response of an operation.
+type errorResponse struct {
+	status int
+	schema *openapi3.SchemaRef
+}
+
+// errorResponses indexes the declared error responses of an operation by error type.
+func errorResponses(op *openapi3.Operation) map[string]errorResponse {
+	responses := make(map[string]errorResponse)
+	for _, methodErr := range operationErrors(op) {
+		if _, ok := responses[methodErr.TypeName]; ok {
+			continue
+		}
+		schema := op.Responses.Status(methodErr.Status).Value.Content.Get("application/json").Schema
+		responses[methodErr.TypeName] = errorResponse{status: methodErr.Status, schema: schema}
+	}
+	return responses
+}
+
+// compareSchema compares two versions of the schema found at location.
+func (c *compatChecker) compareSchema(location string, oldRef, newRef *openapi3.SchemaRef, dir direction) {
+	if oldRef.Ref != "" || newRef.Ref != "" {
+		oldName, newName := refName(oldRef.Ref), refName(newRef.Ref)
+		if oldName != newName {
+			c.report(location, "type-changed", fmt.Sprintf("type changed from %s to %s", schemaTypeName(oldRef), schemaTypeName(newRef)), true)
+			return
+		}
+		// Each named schema is compared once per method and direction; this also stops recursion
+		key := string(dir) + ":" + newName
+		if c.visited[key] {
+			return
+		}
+		c.visited[key] = true
+		location = string(dir) + " " + newName
+	}
+
+	oldSchema, newSchema := oldRef.Value, newRef.Value
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+
+	if oldType, newType := schemaTypeName(oldSchema.NewRef()), schemaTypeName(newSchema.NewRef()); oldType != newType {
+		c.report(location, "type-changed", fmt.Sprintf("type changed from %s to %s", oldType, newType), true)
+		return
+	}
+
+	c.compareEnum(location, oldSchema, newSchema, dir)
+	c.compareConstraints(location, oldSchema, newSchema, dir)
+	c.compareProperties(location, oldSchema, newSchema, dir)
+	c.compareVariants(location, oldSchema, newSchema, dir)
+
+	if oldSchema.Items != nil && newSchema.Items != nil {
+		c.compareSchema(location+"[]", oldSchema.Items, newSchema.Items, dir)
+	}
+	if oldValues, newValues := oldSchema.AdditionalProperties.Schema, newSchema.AdditionalProperties.Schema; oldValues != nil && newValues != nil {
+		c.compareSchema(location+"{}", oldValues, newValues, dir)
+	}
+}
+
+// compareEnum reports removed enum values (persisted data and old clients may still use them)
+// and added values in responses (old clients reject values they do not know).
+func (c *compatChecker) compareEnum(location string, oldSchema, newSchema *openapi3.Schema, dir direction) {
+	if len(oldSchema.Enum) == 0 && len(newSchema.Enum) == 0 {
+		return
+	}
+	oldValues, newValues := enumSet(oldSchema), enumSet(newSchema)
+
+	for _, value := range sortedKeys(oldValues) {
+		if !newValues[value] && len(newSchema.Enum) > 0 {
+			c.report(location, "enum-value-removed", fmt.Sprintf("enum value %s removed", value), true)
+		}
+	}
+	for _, value := range sortedKeys(newValues) {
+		if !oldValues[value] && len(oldSchema.Enum) > 0 {
+			c.report(location, "enum-value-added", fmt.Sprintf("enum value %s added", value), dir == directionResponse)
+		}
+	}
+	if len(oldSchema.Enum) == 0 {
+		c.report(location, "enum-added", "values restricted to an enum", dir == directionRequest)
+	}
+}
+
+// compareConstraints reports constraints that reject values the old schema accepted.
+// Only requests are affected: the actor validates what clients send.
+func (c *compatChecker) compareConstraints(location string, oldSchema, newSchema *openapi3.Schema, dir direction) {
+	if dir != directionRequest {
+		return
+	}
+	narrowed := func(constraint, from, to string) {
+		c.report(location, "constraint-narrowed", fmt.Sprintf("%s narrowed from %s to %s", constraint, from, to), true)
+	}
+
+	if lowerBoundNarrowed(oldSchema.Min, newSchema.Min, oldSchema.ExclusiveMin, newSchema.ExclusiveMin) {
+		narrowed("minimum", formatBound(oldSchema.Min, oldSchema.ExclusiveMin), formatBound(newSchema.Min, newSchema.ExclusiveMin))
+	}
+	if upperBoundNarrowed(oldSchema.Max, newSchema.Max, oldSchema.ExclusiveMax, newSchema.ExclusiveMax) {
+		narrowed("maximum", formatBound(oldSchema.Max, oldSchema.ExclusiveMax), formatBound(newSchema.Max, newSchema.ExclusiveMax))
+	}
+	if newSchema.MinLength > oldSchema.MinLength {
+		narrowed("minLength", fmt.Sprint(oldSchema.MinLength), fmt.Sprint(newSchema.MinLength))
+	}
+	if countNarrowed(oldSchema.MaxLength, newSchema.MaxLength) {
+		narrowed("maxLength", formatCount(oldSchema.MaxLength), formatCount(newSchema.MaxLength))
+	}
+	if newSchema.MinItems > oldSchema.MinItems {
+		narrowed("minItems", fmt.Sprint(oldSchema.MinItems), fmt.Sprint(newSchema.MinItems))
+	}
+	if countNarrowed(oldSchema.MaxItems, newSchema.MaxItems) {
+		narrowed("maxItems", formatCount(oldSchema.MaxItems), formatCount(newSchema.MaxItems))
+	}
+	if newSchema.Pattern != oldSchema.Pattern && newSchema.Pattern != "" {
+		narrowed("pattern", fmt.Sprintf("%q", oldSchema.Pattern), fmt.Sprintf("%q", newSchema.Pattern))
+	}
+}
+
+// compareProperties reports removed properties and changes in which properties are required.
+func (c *compatChecker) compareProperties(location string, oldSchema, newSchema *openapi3.Schema, dir direction) {
+	for _, name := range sortedKeys(oldSchema.Properties) {
+		if _, ok := newSchema.Properties[name]; ok {
+			continue
+		}
+		// Old clients keep sending removed request fields; that only fails for closed schemas
+		breaking := dir == directionResponse || isClosedObject(newSchema)
+		c.report(location+"."+name, "property-removed", "property removed", breaking)
+	}
+
+	for _, name := range sortedKeys(newSchema.Properties) {
+		wasRequired, isRequired := contains(oldSchema.Required, name), contains(newSchema.Required, name)
+		oldProp, existed := oldSchema.Properties[name]
+
+		switch {
+		case !existed && isRequired:
+			c.report(location+"."+name, "required-property-added", "required property added", dir == directionRequest)
+		case !existed:
+			c.report(location+"."+name, "property-added", "optional property added", false)
+		case !wasRequired && isRequired:
+			c.report(location+"."+name, "property-now-required", "property became required", dir == directionRequest)
+		case wasRequired && !isRequired:
+			c.report(location+"."+name, "property-now-optional", "property became optional", dir == directionResponse)
+		}
+
+		if existed {
+			c.compareSchema(location+"."+name, oldProp, newSchema.Properties[name], dir)
+		}
+	}
+}
+
+// compareVariants compares the variants and discriminator of oneOf schemas.
+func (c *compatChecker) compareVariants(location string, oldSchema, newSchema *openapi3.Schema, dir direction) {
+	if len(oldSchema.OneOf) == 0 && len(newSchema.OneOf) == 0 {
+		return
+	}
+
+	if oldSchema.Discriminator != nil && newSchema.Discriminator != nil &&
+		oldSchema.Discriminator.PropertyName != newSchema.Discriminator.PropertyName {
+		c.report(location, "discriminator-changed", fmt.Sprintf("discriminator changed from %s to %s",
+			oldSchema.Discriminator.PropertyName, newSchema.Discriminator.PropertyName), true)
+	}
+
+	oldVariants, newVariants := variantRefs(oldSchema), variantRefs(newSchema)
+	for _, name := range sortedKeys(oldVariants) {
+		if _, ok := newVariants[name]; !ok {
+			// Persisted or in-flight values of a removed variant can no longer be decoded
+			c.report(location, "variant-removed", fmt.Sprintf("oneOf variant %s removed", name), true)
+		}
+	}
+	for _, name := range sortedKeys(newVariants) {
+		oldVariant, ok := oldVariants[name]
+		if !ok {
+			c.report(location, "variant-added", fmt.Sprintf("oneOf variant %s added", name), dir == directionResponse)
+			continue
+		}
+		c.compareSchema(location, oldVariant, newVariants[name], dir)
+	}
+}
+
+// requestSchema returns the JSON request body schema of an operation, if any.
+func requestSchema(op *openapi3.Operation) *openapi3.SchemaRef {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil
+	}
+	if content := op.RequestBody.Value.Content.Get("application/json"); content != nil {
+		return content.Schema
+	}
+	return nil
+}
+
+// responseSchema returns the JSON schema of an operation's 200 response, if any.
+func responseSchema(op *openapi3.Operation) *openapi3.SchemaRef {
+	if op.Responses == nil {
+		return nil
+	}
+	response := op.Responses.Status(200)
+	if response == nil || response.Value == nil {
+		return nil
+	}
+	if content := response.Value.Content.Get("application/json"); content != nil {
+		return content.Schema
+	}
+	return nil
+}
+
+// schemaTypeName describes a schema for messages: its component name, or its Go type.
+func schemaTypeName(schemaRef *openapi3.SchemaRef) string {
+	if schemaRef.Ref != "" {
+		return refName(schemaRef.Ref)
+	}
+	if schemaRef.Value != nil && schemaRef.Value.Type != nil && schemaRef.Value.Type.Is("object") && len(schemaRef.Value.Properties) > 0 {
+		return "object"
+	}
+	return getGoType(schemaRef)
+}
+
+// variantRefs indexes the $ref variants of a oneOf schema by schema name.
+func variantRefs(schema *openapi3.Schema) map[string]*openapi3.SchemaRef {
+	variants := make(map[string]*openapi3.SchemaRef)
+	for _, variant := range schema.OneOf {
+		if variant.Ref != "" {
+			variants[refName(variant.Ref)] = variant
+		}
+	}
+	return variants
+}
+
+func enumSet(schema *openapi3.Schema) map[string]bool {
+	values := make(map[string]bool)
+	for _, value := range schema.Enum {
+		values[fmt.Sprint(value)] = true
+	}
+	return values
+}
+
+// isClosedObject reports whether an object schema rejects undeclared properties.
+func isClosedObject(schema *openapi3.Schema) bool {
+	return schema.AdditionalProperties.Has != nil && !*schema.AdditionalProperties.Has
+}
+
+// lowerBoundNarrowed reports whether a minimum rejects values the old minimum accepted.
+func lowerBoundNarrowed(oldMin, newMin *float64, oldExclusive, newExclusive bool) bool {
+	switch {
+	case newMin == nil:
+		return false
+	case oldMin == nil:
+		return true
+	case *newMin != *oldMin:
+		return *newMin > *oldMin
+	default:
+		return newExclusive && !oldExclusive
+	}
+}
+
+// upperBoundNarrowed reports whether a maximum rejects values the old maximum accepted.
+func upperBoundNarrowed(oldMax, newMax *float64, oldExclusive, newExclusive bool) bool {
+	switch {
+	case newMax == nil:
+		return false
+	case oldMax == nil:
+		return true
+	case *newMax != *oldMax:
+		return *newMax < *oldMax
+	default:
+		return newExclusive && !oldExclusive
+	}
+}
+
+// countNarrowed reports whether a maxLength/maxItems limit rejects values the old one accepted.
+func countNarrowed(oldMax, newMax *uint64) bool {
+	return newMax != nil && (oldMax == nil || *newMax < *oldMax)
+}
+
+func formatBound(bound *float64, exclusive bool) string {
+	if bound == nil {
+		return "none"
+	}
+	if exclusive {
+		return fmt.Sprintf("%v (exclusive)", *bound)
+	}
+	return fmt.Sprint(*bound)
+}
+
+func formatCount(count *uint64) string {
+	if count == nil {
+		return "none"
+	}
+	return fmt.Sprint(*count)
+}
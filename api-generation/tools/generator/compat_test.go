@@ -0,0 +1,271 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// compatBaseSpec is the old version of the spec that each test case edits.
+const compatBaseSpec = `openapi: 3.0.3
+info:
+  title: Compat
+  version: 1.0.0
+paths:
+  /WalletActor/{actorId}/method/deposit:
+    post:
+      tags: ["ActorType:WalletActor"]
+      parameters:
+        - name: actorId
+          in: path
+          required: true
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/DepositRequest'
+      responses:
+        '200':
+          description: New wallet state
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/WalletState'
+        '404':
+          description: Wallet not found
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/WalletNotFoundError'
+components:
+  schemas:
+    Currency:
+      type: string
+      enum: [EUR, USD]
+    DepositRequest:
+      type: object
+      required: [amount]
+      properties:
+        amount:
+          type: number
+          minimum: 0.01
+        currency:
+          $ref: '#/components/schemas/Currency'
+        note:
+          type: string
+    WalletState:
+      type: object
+      required: [balance]
+      properties:
+        balance:
+          type: number
+        owner:
+          type: string
+    WalletNotFoundError:
+      type: object
+      required: [message]
+      properties:
+        message:
+          type: string
+`
+
+func loadCompatSpec(t *testing.T, spec string) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+	return doc
+}
+
+func TestCompareSpecs(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string // Replaced in compatBaseSpec to derive the new version
+		kinds    []string
+		breaking bool
+	}{
+		{
+			name: "unchanged",
+		},
+		{
+			name:     "response property removed",
+			old:      "        owner:\n          type: string\n",
+			kinds:    []string{"property-removed"},
+			breaking: true,
+		},
+		{
+			name:     "request property removed from open object",
+			old:      "        note:\n          type: string\n",
+			kinds:    []string{"property-removed"},
+			breaking: false,
+		},
+		{
+			name:     "property type changed",
+			old:      "        owner:\n          type: string\n",
+			new:      "        owner:\n          type: integer\n",
+			kinds:    []string{"type-changed"},
+			breaking: true,
+		},
+		{
+			name:     "request body type changed",
+			old:      "              $ref: '#/components/schemas/DepositRequest'",
+			new:      "              $ref: '#/components/schemas/WalletState'",
+			kinds:    []string{"type-changed"},
+			breaking: true,
+		},
+		{
+			name:     "request property became required",
+			old:      "      required: [amount]\n",
+			new:      "      required: [amount, note]\n",
+			kinds:    []string{"property-now-required"},
+			breaking: true,
+		},
+		{
+			name:     "required request property added",
+			old:      "      required: [amount]\n      properties:\n",
+			new:      "      required: [amount, reference]\n      properties:\n        reference:\n          type: string\n",
+			kinds:    []string{"required-property-added"},
+			breaking: true,
+		},
+		{
+			name:     "optional response property added",
+			old:      "        owner:\n          type: string\n",
+			new:      "        owner:\n          type: string\n        label:\n          type: string\n",
+			kinds:    []string{"property-added"},
+			breaking: false,
+		},
+		{
+			name:     "enum narrowed",
+			old:      "enum: [EUR, USD]",
+			new:      "enum: [EUR]",
+			kinds:    []string{"enum-value-removed"},
+			breaking: true,
+		},
+		{
+			name:     "enum widened in request",
+			old:      "enum: [EUR, USD]",
+			new:      "enum: [EUR, GBP, USD]",
+			kinds:    []string{"enum-value-added"},
+			breaking: false,
+		},
+		{
+			name:     "minimum narrowed",
+			old:      "minimum: 0.01",
+			new:      "minimum: 1",
+			kinds:    []string{"constraint-narrowed"},
+			breaking: true,
+		},
+		{
+			name:     "error status changed",
+			old:      "        '404':\n          description: Wallet not found",
+			new:      "        '410':\n          description: Wallet not found",
+			kinds:    []string{"error-status-changed"},
+			breaking: true,
+		},
+		{
+			name:     "error code changed",
+			old:      "    WalletNotFoundError:\n      type: object\n",
+			new:      "    WalletNotFoundError:\n      type: object\n      x-error-code: no_wallet\n",
+			kinds:    []string{"error-code-changed"},
+			breaking: true,
+		},
+		{
+			name:     "error body changed",
+			old:      "        message:\n          type: string\n",
+			new:      "        message:\n          type: integer\n",
+			kinds:    []string{"type-changed"},
+			breaking: true,
+		},
+		{
+			name: "error removed",
+			old: "        '404':\n          description: Wallet not found\n          content:\n            application/json:\n" +
+				"              schema:\n                $ref: '#/components/schemas/WalletNotFoundError'\n",
+			kinds:    []string{"error-removed"},
+			breaking: false,
+		},
+		{
+			name: "error added",
+			old:  "        '404':\n",
+			new: "        '409':\n          description: Wallet locked\n          content:\n            application/json:\n" +
+				"              schema:\n                $ref: '#/components/schemas/WalletLockedError'\n        '404':\n",
+			kinds:    []string{"error-added"},
+			breaking: true,
+		},
+		{
+			name:     "method removed",
+			old:      "/WalletActor/{actorId}/method/deposit:",
+			new:      "/WalletActor/{actorId}/method/credit:",
+			kinds:    []string{"method-removed", "method-added"},
+			breaking: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newSpec := compatBaseSpec
+			if tt.old != "" {
+				if !strings.Contains(newSpec, tt.old) {
+					t.Fatalf("base spec does not contain %q", tt.old)
+				}
+				newSpec = strings.Replace(newSpec, tt.old, tt.new, 1)
+			}
+			if strings.Contains(newSpec, "WalletLockedError'") {
+				newSpec += "    WalletLockedError:\n      type: object\n      properties:\n        message:\n          type: string\n"
+			}
+
+			report := compareSpecs(loadCompatSpec(t, compatBaseSpec), loadCompatSpec(t, newSpec))
+
+			var kinds []string
+			for _, change := range report.Changes {
+				kinds = append(kinds, change.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tt.kinds) {
+				t.Errorf("changes = %+v, want kinds %v", report.Changes, tt.kinds)
+			}
+			if report.Breaking != tt.breaking {
+				t.Errorf("breaking = %v, want %v", report.Breaking, tt.breaking)
+			}
+		})
+	}
+}
+
+func TestCompareSpecsLocation(t *testing.T) {
+	newSpec := strings.Replace(compatBaseSpec, "minimum: 0.01", "minimum: 1", 1)
+	report := compareSpecs(loadCompatSpec(t, compatBaseSpec), loadCompatSpec(t, newSpec))
+
+	want := []Change{{
+		ActorType: "WalletActor",
+		Method:    "Deposit",
+		Location:  "request DepositRequest.amount",
+		Kind:      "constraint-narrowed",
+		Message:   "minimum narrowed from 0.01 to 1",
+		Breaking:  true,
+	}}
+	if !reflect.DeepEqual(report.Changes, want) {
+		t.Errorf("changes = %+v, want %+v", report.Changes, want)
+	}
+}
+
+func TestConfigOldSpecPaths(t *testing.T) {
+	old := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(old, "specs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(old, "specs", "wallet.yaml"), []byte(compatBaseSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// shop.yaml is new, so it has no old version
+	config := &Config{Specs: []string{"specs/wallet.yaml", "specs/shop.yaml"}, dir: "project"}
+	want := []string{filepath.Join(old, "specs", "wallet.yaml")}
+	if got := config.oldSpecPaths(old); !reflect.DeepEqual(got, want) {
+		t.Errorf("oldSpecPaths = %v, want %v", got, want)
+	}
+}
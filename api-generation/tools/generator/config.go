@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Artefacts that can be generated for an actor package.
+const (
+	artefactTypes     = "types"
+	artefactInterface = "interface"
+	artefactFactory   = "factory" // also reminders.go when the actor declares reminders or timers
+	artefactClient    = "client"
+	artefactMock      = "mock"
+	artefactContract  = "contract" // contract_test.go, exercising the implementation with the schema examples
+)
+
+// allArtefacts lists every artefact in generation order; it is the default set.
+var allArtefacts = []string{artefactTypes, artefactInterface, artefactFactory, artefactClient, artefactMock, artefactContract}
+
+// artefactRequires lists the artefacts whose declarations each artefact's code uses.
+var artefactRequires = map[string][]string{
+	artefactInterface: {artefactTypes},
+	artefactFactory:   {artefactTypes, artefactInterface},
+	artefactClient:    {artefactTypes, artefactInterface},
+	artefactMock:      {artefactTypes, artefactInterface},
+	artefactContract:  {artefactTypes, artefactInterface, artefactFactory},
+}
+
+// Config is the generator configuration, read from a YAML file with --config:
+//
+//	specs:
+//	  - api-generation/schemas/openapi
+//	output: internal
+//	module: github.com/example/project
+//	registry: internal/actors
+//	typescript: clients/typescript
+//	actors:
+//	  BankAccountActor:
+//	    package: accounts
+//	    output: internal/accounts
+//	    implementation: Account
+//	    generate: [types, interface, factory, client]
+//
+// Paths are relative to the directory of the config file. Without a config file, the spec
+// paths and output directory come from the command line and everything else uses the defaults.
+type Config struct {
+	Specs      []string               `yaml:"specs"`      // Spec files or directories
+	Output     string                 `yaml:"output"`     // Base output directory for actor and common packages
+	Module     string                 `yaml:"module"`     // Go import path of the config file's directory; default: from go.mod
+	Generate   []string               `yaml:"generate"`   // Artefacts generated for every actor; default: all
+	Actors     map[string]ActorConfig `yaml:"actors"`     // Per-actor settings by actor type
+	Templates  string                 `yaml:"templates"`  // Template override directory; --templates takes precedence
+	Registry   string                 `yaml:"registry"`   // Directory of the registry package; default: <output>/registry
+	TypeScript string                 `yaml:"typescript"` // Directory of the TypeScript client; default: not generated
+
+	dir string // Directory the config was read from; paths are relative to it
+}
+
+// ActorConfig holds the settings of one actor type. Empty fields use the defaults.
+type ActorConfig struct {
+	Package        string   `yaml:"package"`        // Default: lowercased actor type with an "actor" suffix
+	Output         string   `yaml:"output"`         // Default: <output>/<package>
+	Implementation string   `yaml:"implementation"` // Struct instantiated by NewActorFactory; default: the actor type
+	Generate       []string `yaml:"generate"`       // Default: the top-level generate list
+}
+
+// loadConfig reads and validates a generator config file.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{dir: filepath.Dir(path)}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	if len(c.Specs) == 0 {
+		return fmt.Errorf("specs is required")
+	}
+	if c.Output == "" {
+		return fmt.Errorf("output is required")
+	}
+	if err := validateArtefacts(c.Generate); err != nil {
+		return err
+	}
+	if name := filepath.Base(c.registryDir()); !isIdentifier(name) || name != strings.ToLower(name) {
+		return fmt.Errorf("registry: invalid package name %q", name)
+	}
+
+	packages := make(map[string]string)
+	for _, actorType := range sortedKeys(c.Actors) {
+		actor := c.Actors[actorType]
+		if err := validateArtefacts(actor.Generate); err != nil {
+			return fmt.Errorf("actors.%s: %v", actorType, err)
+		}
+		packageName := c.packageName(actorType)
+		if !isIdentifier(packageName) || packageName != strings.ToLower(packageName) {
+			return fmt.Errorf("actors.%s: invalid package name %q", actorType, packageName)
+		}
+		if actor.Implementation != "" && !isIdentifier(actor.Implementation) {
+			return fmt.Errorf("actors.%s: invalid implementation %q", actorType, actor.Implementation)
+		}
+		if other, ok := packages[c.outputDir(actorType)]; ok {
+			return fmt.Errorf("actors %s and %s are generated into the same directory", other, actorType)
+		}
+		packages[c.outputDir(actorType)] = actorType
+	}
+	return nil
+}
+
+// validateArtefacts checks that every artefact is known and its dependencies are generated too.
+func validateArtefacts(artefacts []string) error {
+	for _, artefact := range artefacts {
+		if !contains(allArtefacts, artefact) {
+			return fmt.Errorf("unknown artefact %q (expected one of %s)", artefact, strings.Join(allArtefacts, ", "))
+		}
+		for _, required := range artefactRequires[artefact] {
+			if !contains(artefacts, required) {
+				return fmt.Errorf("artefact %q requires %q", artefact, required)
+			}
+		}
+	}
+	return nil
+}
+
+// path resolves a path from the config file against the config's directory.
+func (c *Config) path(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.dir, path)
+}
+
+// specPaths returns the spec files and directories to load.
+func (c *Config) specPaths() []string {
+	paths := make([]string, len(c.Specs))
+	for i, spec := range c.Specs {
+		paths[i] = c.path(spec)
+	}
+	return paths
+}
+
+// oldSpecPaths returns the spec paths of the config resolved against oldDir, a copy of the
+// config's directory at an earlier version. Specs that did not exist yet are left out.
+func (c *Config) oldSpecPaths(oldDir string) []string {
+	var paths []string
+	for _, spec := range c.Specs {
+		if filepath.IsAbs(spec) {
+			continue
+		}
+		path := filepath.Join(oldDir, spec)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// packageName returns the Go package generated for actorType.
+func (c *Config) packageName(actorType string) string {
+	if name := c.Actors[actorType].Package; name != "" {
+		return name
+	}
+	packageName := strings.ToLower(actorType)
+	if !strings.HasSuffix(packageName, "actor") {
+		packageName += "actor"
+	}
+	return packageName
+}
+
+// outputDir returns the directory the package of actorType is generated into.
+func (c *Config) outputDir(actorType string) string {
+	if output := c.Actors[actorType].Output; output != "" {
+		return c.path(output)
+	}
+	return filepath.Join(c.path(c.Output), c.packageName(actorType))
+}
+
+// registryDir returns the directory the registry package is generated into.
+func (c *Config) registryDir() string {
+	if c.Registry != "" {
+		return c.path(c.Registry)
+	}
+	return filepath.Join(c.path(c.Output), registryPackageName)
+}
+
+// implementation returns the struct NewActorFactory instantiates for actorType.
+func (c *Config) implementation(actorType string) string {
+	if implementation := c.Actors[actorType].Implementation; implementation != "" {
+		return implementation
+	}
+	return actorType
+}
+
+// generates reports whether artefact is generated for actorType.
+func (c *Config) generates(actorType, artefact string) bool {
+	artefacts := c.Actors[actorType].Generate
+	if artefacts == nil {
+		artefacts = c.Generate
+	}
+	return artefacts == nil || contains(artefacts, artefact)
+}
+
+// importPath returns the Go import path of dir, from the configured module path or else
+// the nearest go.mod above dir.
+func (c *Config) importPath(dir string) (string, error) {
+	if c.Module == "" {
+		return resolveImportPath(dir)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	absRoot, err := filepath.Abs(c.dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absRoot, absDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside of module %s", dir, c.Module)
+	}
+	if rel == "." {
+		return c.Module, nil
+	}
+	return c.Module + "/" + filepath.ToSlash(rel), nil
+}
+
+// isIdentifier reports whether s is a valid Go identifier.
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
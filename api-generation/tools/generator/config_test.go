@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string // Substring of the expected error; "" when the config is valid
+	}{
+		{
+			name: "valid config",
+			config: `
+specs: [specs]
+output: internal
+registry: internal/actors
+actors:
+  BankAccountActor:
+    package: accounts
+    implementation: Account
+    generate: [types, interface, factory, client]`,
+		},
+		{
+			name:   "unknown field",
+			config: "specs: [specs]\noutput: internal\noutputs: gen",
+			err:    "field outputs not found",
+		},
+		{
+			name:   "unknown actor field",
+			config: "specs: [specs]\noutput: internal\nactors:\n  CounterActor:\n    pkg: counter",
+			err:    "field pkg not found",
+		},
+		{
+			name:   "missing specs",
+			config: "output: internal",
+			err:    "specs is required",
+		},
+		{
+			name:   "missing output",
+			config: "specs: [specs]",
+			err:    "output is required",
+		},
+		{
+			name:   "unknown artefact",
+			config: "specs: [specs]\noutput: internal\ngenerate: [types, stubs]",
+			err:    `unknown artefact "stubs"`,
+		},
+		{
+			name:   "missing required artefact",
+			config: "specs: [specs]\noutput: internal\ngenerate: [types, client]",
+			err:    `artefact "client" requires "interface"`,
+		},
+		{
+			name:   "missing required actor artefact",
+			config: "specs: [specs]\noutput: internal\nactors:\n  CounterActor:\n    generate: [interface]",
+			err:    `actors.CounterActor: artefact "interface" requires "types"`,
+		},
+		{
+			name:   "invalid registry package",
+			config: "specs: [specs]\noutput: internal\nregistry: internal/actor-registry",
+			err:    `registry: invalid package name "actor-registry"`,
+		},
+		{
+			name:   "invalid package",
+			config: "specs: [specs]\noutput: internal\nactors:\n  CounterActor:\n    package: Counter",
+			err:    `actors.CounterActor: invalid package name "Counter"`,
+		},
+		{
+			name:   "invalid implementation",
+			config: "specs: [specs]\noutput: internal\nactors:\n  CounterActor:\n    implementation: counter.Actor",
+			err:    `actors.CounterActor: invalid implementation "counter.Actor"`,
+		},
+		{
+			name:   "same output directory",
+			config: "specs: [specs]\noutput: internal\nactors:\n  CounterActor:\n    package: shared\n  WalletActor:\n    package: shared",
+			err:    "actors CounterActor and WalletActor are generated into the same directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "generator.yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := loadConfig(path)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	config := &Config{
+		Output:   "internal",
+		Generate: []string{artefactTypes, artefactInterface},
+		Actors: map[string]ActorConfig{
+			"BankAccountActor": {Package: "accounts", Implementation: "Account", Generate: []string{artefactTypes}},
+		},
+		dir: "project",
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"default package", config.packageName("CounterActor"), "counteractor"},
+		{"package keeps actor suffix", config.packageName("Wallet"), "walletactor"},
+		{"configured package", config.packageName("BankAccountActor"), "accounts"},
+		{"default output", config.outputDir("CounterActor"), filepath.Join("project", "internal", "counteractor")},
+		{"configured package output", config.outputDir("BankAccountActor"), filepath.Join("project", "internal", "accounts")},
+		{"default registry", config.registryDir(), filepath.Join("project", "internal", "registry")},
+		{"default implementation", config.implementation("CounterActor"), "CounterActor"},
+		{"configured implementation", config.implementation("BankAccountActor"), "Account"},
+		{"top-level generate", config.generates("CounterActor", artefactInterface), true},
+		{"top-level generate excludes", config.generates("CounterActor", artefactClient), false},
+		{"actor generate overrides", config.generates("BankAccountActor", artefactInterface), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
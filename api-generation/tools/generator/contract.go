@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ContractMethod is an actor method exercised by the generated contract test.
+type ContractMethod struct {
+	Method
+	Request   string // Go string literal of the example request JSON
+	Skip      string // Reason the method cannot be exercised, e.g. a request property without an example
+	HasResult bool   // The 200 response declares a schema the result is checked against
+}
+
+type ContractTemplateData struct {
+	PackageName string
+	Actor       ActorInterface
+	Methods     []ContractMethod
+	Runnable    bool // At least one method is not skipped
+}
+
+// generateActorContract writes contract_test.go, which invokes every method of the actor with the
+// request built from the OpenAPI examples and checks the responses against the schema.
+func generateActorContract(doc *openapi3.T, packageName, outputDir, actorType string, methods []Method, out *outputWriter) error {
+	tmpl, err := loadTemplate("contract.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to parse contract template: %v", err)
+	}
+
+	data := ContractTemplateData{
+		PackageName: packageName,
+		Actor: ActorInterface{
+			ActorType: actorType,
+			Methods:   methods,
+			Errors:    actorErrorTypes(methods),
+		},
+	}
+
+	operations := actorOperations(doc, actorType)
+	for _, method := range methods {
+		contract := ContractMethod{Method: method, HasResult: method.ReturnType != "interface{}"}
+		if method.HasRequest {
+			request, err := requestExample(operations[method.Name])
+			if err != nil {
+				contract.Skip = fmt.Sprintf("no example request: %v", err)
+			} else {
+				contract.Request = goStringLiteral(request)
+			}
+		}
+		if contract.Skip == "" {
+			data.Runnable = true
+		}
+		data.Methods = append(data.Methods, contract)
+	}
+
+	return out.writeGoFile(filepath.Join(outputDir, "contract_test.go"), tmpl, data)
+}
+
+// actorOperations maps the method names of actorType to their operations.
+func actorOperations(doc *openapi3.T, actorType string) map[string]*openapi3.Operation {
+	operations := make(map[string]*openapi3.Operation)
+	paths := doc.Paths.Map()
+	for _, path := range sortedKeys(paths) {
+		for _, httpMethod := range httpMethods {
+			op := paths[path].GetOperation(httpMethod)
+			if op != nil && getOperationActorType(op) == actorType {
+				operations[strings.Title(extractMethodNameFromPath(path))] = op
+			}
+		}
+	}
+	return operations
+}
+
+// requestExample returns the JSON request of an operation: the example of its JSON request body,
+// or else a value assembled from the examples of the body schema.
+func requestExample(op *openapi3.Operation) (string, error) {
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return "", fmt.Errorf("operation has no request body")
+	}
+	mediaType := op.RequestBody.Value.Content.Get("application/json")
+	if mediaType == nil {
+		return "", fmt.Errorf("request body has no application/json content")
+	}
+
+	example := mediaType.Example
+	if example == nil && len(mediaType.Examples) > 0 {
+		if first := mediaType.Examples[sortedKeys(mediaType.Examples)[0]]; first.Value != nil {
+			example = first.Value.Value
+		}
+	}
+	if example == nil {
+		var err error
+		if example, err = schemaExample(mediaType.Schema, ""); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(example)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// schemaExample assembles an example value for a schema from its example, default or first enum
+// value. Objects are built from the examples of their properties; optional properties without one
+// are left out. path names the property in errors.
+func schemaExample(schemaRef *openapi3.SchemaRef, path string) (interface{}, error) {
+	if schemaRef == nil || schemaRef.Value == nil {
+		return nil, fmt.Errorf("%s has no schema", describeExamplePath(path))
+	}
+	schema := schemaRef.Value
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example, nil
+	case schema.Default != nil:
+		return schema.Default, nil
+	case len(schema.Enum) > 0:
+		return schema.Enum[0], nil
+	case len(schema.OneOf) > 0:
+		return schemaExample(schema.OneOf[0], path)
+	case schema.Type.Is("array"):
+		item, err := schemaExample(schema.Items, path+"[]")
+		if err != nil {
+			if schema.MinItems == 0 {
+				return []interface{}{}, nil
+			}
+			return nil, err
+		}
+		return []interface{}{item}, nil
+	case schema.Type.Is("object") || len(schema.Properties) > 0:
+		object := make(map[string]interface{})
+		for _, name := range sortedKeys(schema.Properties) {
+			value, err := schemaExample(schema.Properties[name], strings.TrimPrefix(path+"."+name, "."))
+			if err != nil {
+				if contains(schema.Required, name) {
+					return nil, err
+				}
+				continue
+			}
+			object[name] = value
+		}
+		return object, nil
+	}
+	return nil, fmt.Errorf("%s has no example", describeExamplePath(path))
+}
+
+func describeExamplePath(path string) string {
+	if path == "" {
+		return "request body"
+	}
+	return "property " + path
+}
+
+// goStringLiteral quotes s as a raw string literal when it contains no backquote.
+func goStringLiteral(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// contractSpec declares a method with a response and Ping, which has neither a response schema
+// nor declared errors.
+const contractSpec = `openapi: 3.0.3
+info:
+  title: Shop
+  version: 1.0.0
+paths:
+  /ShopActor/{actorId}/method/getItems:
+    get:
+      operationId: getItems
+      tags: [ActorType:ShopActor]
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Items'
+  /ShopActor/{actorId}/method/ping:
+    post:
+      operationId: ping
+      tags: [ActorType:ShopActor]
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    Items:
+      type: object
+      properties:
+        names:
+          type: array
+          items:
+            type: string
+`
+
+const contractImplementation = `package shopactor
+
+import (
+	"context"
+
+	"github.com/dapr/go-sdk/actor"
+)
+
+type ShopActor struct {
+	actor.ServerImplBaseCtx
+}
+
+func (a *ShopActor) Type() string { return ActorTypeShopActor }
+
+func (a *ShopActor) GetItems(ctx context.Context) (*Items, error) {
+	return &Items{Names: []string{"apple"}}, nil
+}
+
+func (a *ShopActor) Ping(ctx context.Context) (*interface{}, error) { return nil, nil }
+`
+
+// TestContractCompiles generates an actor package into a module using the root module's
+// dependencies and checks that the package and its contract test compile.
+func TestContractCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+	goMod, err := os.ReadFile(filepath.Join("..", "..", "..", "go.mod"))
+	if err != nil {
+		t.Skipf("root module not found: %v", err)
+	}
+	goSum, err := os.ReadFile(filepath.Join("..", "..", "..", "go.sum"))
+	if err != nil {
+		t.Skipf("root module not found: %v", err)
+	}
+	_, requires, _ := strings.Cut(string(goMod), "\n")
+
+	dir := writeSpecFiles(t, map[string]string{
+		"shop.yaml":                       contractSpec,
+		"go.mod":                          "module example.com/shop\n" + requires,
+		"go.sum":                          string(goSum),
+		"internal/shopactor/shopactor.go": contractImplementation,
+	})
+	config := &Config{Specs: []string{"shop.yaml"}, Output: "internal", Module: "example.com/shop", dir: dir}
+	doc, err := loadSpecs(config.specPaths())
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+	if err := generateActorPackages(doc, config, &outputWriter{}); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	source, err := os.ReadFile(filepath.Join(dir, "internal", "shopactor", "contract_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(source), `const contractActorID = "shop-actor-contract"`) {
+		t.Errorf("contract test does not derive its actor ID from the actor type:\n%s", source)
+	}
+
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated code does not compile: %v\n%s", err, output)
+	}
+}
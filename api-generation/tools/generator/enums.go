@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// EnumDef is a named Go type generated from a string or integer enum schema.
+type EnumDef struct {
+	Name        string
+	Description string
+	BaseType    string
+	IsString    bool
+	Values      []EnumValue
+}
+
+// EnumValue is a single constant of an enum type.
+type EnumValue struct {
+	ConstName string
+	Literal   string // Go literal of the value (quoted for strings)
+}
+
+// isEnum reports whether schema declares a closed set of string or integer values.
+func isEnum(schema *openapi3.Schema) bool {
+	return schema != nil && len(schema.Enum) > 0 && (schema.Type.Is("string") || schema.Type.Is("integer"))
+}
+
+// addEnum generates a named enum type called name for an enum schema.
+func (b *structBuilder) addEnum(name string, schema *openapi3.Schema) {
+	enum := EnumDef{
+		Name:        name,
+		Description: schema.Description,
+		BaseType:    getGoType(openapi3.NewSchemaRef("", schema)),
+		IsString:    schema.Type.Is("string"),
+	}
+
+	enum.Values = enumValues(name, schema)
+
+	b.enums = append(b.enums, enum)
+}
+
+// enumValues returns the constants generated for an enum type called name.
+func enumValues(name string, schema *openapi3.Schema) []EnumValue {
+	var values []EnumValue
+	for _, value := range schema.Enum {
+		var literal, suffix string
+		if schema.Type.Is("string") {
+			str := fmt.Sprint(value)
+			literal = strconv.Quote(str)
+			suffix = enumConstSuffix(str)
+		} else {
+			// YAML/JSON numbers decode as float64
+			number := int64(toFloat(value))
+			literal = strconv.FormatInt(number, 10)
+			suffix = strconv.FormatInt(number, 10)
+			if number < 0 {
+				suffix = "Minus" + strconv.FormatInt(-number, 10)
+			}
+		}
+		values = append(values, EnumValue{
+			ConstName: name + suffix,
+			Literal:   literal,
+		})
+	}
+	return values
+}
+
+// enumValidation returns the check that a field of enum type holds a declared value.
+// Optional fields may also hold the zero value, meaning "not sent".
+func enumValidation(expr string, schema *openapi3.Schema, required bool) Validation {
+	condition := fmt.Sprintf("!%s.IsValid()", expr)
+	if !required {
+		zero := "0"
+		if schema.Type.Is("string") {
+			zero = `""`
+		}
+		condition = fmt.Sprintf("%s != %s && %s", expr, zero, condition)
+	}
+
+	values := make([]string, 0, len(schema.Enum))
+	for _, value := range schema.Enum {
+		values = append(values, fmt.Sprint(value))
+	}
+
+	return Validation{
+		Condition: condition,
+		Message:   "must be one of " + strings.Join(values, ", "),
+	}
+}
+
+// enumConstSuffix converts an enum value such as "money-deposited" into "MoneyDeposited".
+func enumConstSuffix(value string) string {
+	var result strings.Builder
+	upperNext := true
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		result.WriteRune(r)
+	}
+	if result.Len() == 0 {
+		return "Empty"
+	}
+	return result.String()
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		f, _ := strconv.ParseFloat(fmt.Sprint(v), 64)
+		return f
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// errorEnvelopeKey is the property under which the generated server returns declared errors.
+// Response schemas must not declare it, or results could not be told apart from errors.
+const errorEnvelopeKey = "actorError"
+
+// MethodError is an error response declared for an operation.
+type MethodError struct {
+	TypeName  string // Go type of the error (the response schema name)
+	CodeConst string // Constant holding the error's stable code
+	Status    int    // HTTP status the error is declared under
+}
+
+// ErrorSchema describes a component schema used as a 4xx/5xx response body.
+type ErrorSchema struct {
+	Code      string // Stable error code sent across the actor boundary
+	CodeConst string
+}
+
+// collectErrorSchemas maps every component schema referenced by a 4xx or 5xx response to its error code.
+func collectErrorSchemas(doc *openapi3.T) (map[string]ErrorSchema, error) {
+	errorSchemas := make(map[string]ErrorSchema)
+	owners := make(map[string]string)
+
+	paths := doc.Paths.Map()
+	for _, path := range sortedKeys(paths) {
+		pathItem := paths[path]
+		for _, httpMethod := range httpMethods {
+			op := pathItem.GetOperation(httpMethod)
+			if op == nil {
+				continue
+			}
+			for _, methodErr := range operationErrors(op) {
+				if _, ok := errorSchemas[methodErr.TypeName]; ok {
+					continue
+				}
+				schemaRef := doc.Components.Schemas[methodErr.TypeName]
+				if schemaRef == nil || schemaRef.Value == nil || !schemaRef.Value.Type.Is("object") || schemaRef.Value.Properties == nil {
+					return nil, fmt.Errorf("error response %s of %s %s must reference an object schema", methodErr.TypeName, httpMethod, path)
+				}
+
+				code := errorCode(methodErr.TypeName, schemaRef.Value)
+				if owner, ok := owners[code]; ok {
+					return nil, fmt.Errorf("error code %q is used by both %s and %s", code, owner, methodErr.TypeName)
+				}
+				owners[code] = methodErr.TypeName
+				errorSchemas[methodErr.TypeName] = ErrorSchema{Code: code, CodeConst: methodErr.CodeConst}
+			}
+
+			if schemaRef := responseSchema(op); schemaRef != nil && schemaRef.Value != nil && schemaRef.Value.Properties[errorEnvelopeKey] != nil {
+				return nil, fmt.Errorf("response of %s %s declares reserved property %q", httpMethod, path, errorEnvelopeKey)
+			}
+		}
+	}
+
+	return errorSchemas, nil
+}
+
+// errorCode returns the stable code of the error response schema typeName: its x-error-code
+// extension, or the snake_cased schema name without an "Error" suffix.
+func errorCode(typeName string, schema *openapi3.Schema) string {
+	if schema != nil {
+		if ext, ok := schema.Extensions["x-error-code"].(string); ok && ext != "" {
+			return ext
+		}
+	}
+	return toSnakeCase(strings.TrimSuffix(typeName, "Error"))
+}
+
+// operationErrors returns the error responses of an operation whose JSON body is a component $ref, ordered by status.
+func operationErrors(op *openapi3.Operation) []MethodError {
+	if op.Responses == nil {
+		return nil
+	}
+
+	var errs []MethodError
+	responses := op.Responses.Map()
+	for _, key := range sortedKeys(responses) {
+		status, err := strconv.Atoi(key)
+		if err != nil || status < 400 || status > 599 {
+			continue
+		}
+		response := responses[key]
+		if response.Value == nil || response.Value.Content == nil {
+			continue
+		}
+		jsonContent := response.Value.Content.Get("application/json")
+		if jsonContent == nil || jsonContent.Schema == nil || jsonContent.Schema.Ref == "" {
+			continue
+		}
+		typeName := refName(jsonContent.Schema.Ref)
+		errs = append(errs, MethodError{
+			TypeName:  typeName,
+			CodeConst: "ErrorCode" + strings.TrimSuffix(typeName, "Error"),
+			Status:    status,
+		})
+	}
+	return errs
+}
+
+// hasMethodErrors reports whether any of the methods declares an error response.
+func hasMethodErrors(methods []Method) bool {
+	for _, method := range methods {
+		if len(method.Errors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// actorErrorTypes returns the distinct error types declared by methods, in order of first use.
+func actorErrorTypes(methods []Method) []MethodError {
+	var types []MethodError
+	seen := make(map[string]bool)
+	for _, method := range methods {
+		for _, methodErr := range method.Errors {
+			if !seen[methodErr.TypeName] {
+				seen[methodErr.TypeName] = true
+				types = append(types, methodErr)
+			}
+		}
+	}
+	return types
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// dateTypeName is the civil date type generated into packages that use format: date.
+const dateTypeName = "Date"
+
+// formatTypes maps string formats to Go types that keep their JSON representation.
+var formatTypes = map[string]GoTypeRef{
+	"date-time": {Type: "time.Time", Import: "time"},
+	"date":      {Type: dateTypeName},
+	"uuid":      {Type: "uuid.UUID", Import: "github.com/google/uuid"},
+}
+
+// GoTypeRef is a Go type together with the package that must be imported to use it.
+type GoTypeRef struct {
+	Type   string
+	Import string
+}
+
+// customGoType returns the type named by the x-go-type extension. The value is either a
+// package-qualified type with x-go-type-import naming the package:
+//
+//	x-go-type: decimal.Decimal
+//	x-go-type-import: github.com/shopspring/decimal
+//
+// or the type prefixed with its import path ("github.com/shopspring/decimal.Decimal").
+// The type must marshal to and from the JSON the schema describes.
+func customGoType(schema *openapi3.Schema) (GoTypeRef, bool) {
+	if schema == nil {
+		return GoTypeRef{}, false
+	}
+	goType, ok := schema.Extensions["x-go-type"].(string)
+	if !ok || goType == "" {
+		return GoTypeRef{}, false
+	}
+	if importPath, ok := schema.Extensions["x-go-type-import"].(string); ok {
+		return GoTypeRef{Type: goType, Import: importPath}, true
+	}
+
+	// Split "github.com/shopspring/decimal.Decimal" into the import path and "decimal.Decimal"
+	slash := strings.LastIndex(goType, "/")
+	dot := strings.LastIndex(goType, ".")
+	if slash < 0 || dot < slash {
+		return GoTypeRef{Type: goType}, true
+	}
+	importPath := goType[:dot]
+	return GoTypeRef{Type: path.Base(importPath) + goType[dot:], Import: importPath}, true
+}
+
+// formatGoType returns the rich Go type for a schema with x-go-type or a known string format.
+func formatGoType(schema *openapi3.Schema) (GoTypeRef, bool) {
+	if ref, ok := customGoType(schema); ok {
+		return ref, true
+	}
+	if schema != nil && schema.Type.Is("string") {
+		ref, ok := formatTypes[schema.Format]
+		return ref, ok
+	}
+	return GoTypeRef{}, false
+}
+
+// goTypeImports returns the packages imported by the Go type of a schema, including array
+// items and map values, and whether the type uses the generated Date type.
+func goTypeImports(schemaRef *openapi3.SchemaRef) (imports []string, usesDate bool) {
+	if schemaRef == nil || schemaRef.Value == nil {
+		return nil, false
+	}
+	schema := schemaRef.Value
+	if ref, ok := formatGoType(schema); ok && (schemaRef.Ref == "" || isExternalType(schema)) {
+		if ref.Import != "" {
+			imports = append(imports, ref.Import)
+		}
+		return imports, ref.Type == dateTypeName
+	}
+	if schemaRef.Ref != "" {
+		return nil, false // Named types import their own dependencies
+	}
+
+	var nested []*openapi3.SchemaRef
+	if schema.Type.Is("array") {
+		nested = append(nested, schema.Items)
+	}
+	if schema.Type.Is("object") {
+		nested = append(nested, schema.AdditionalProperties.Schema)
+	}
+	for _, ref := range nested {
+		nestedImports, nestedDate := goTypeImports(ref)
+		imports = append(imports, nestedImports...)
+		usesDate = usesDate || nestedDate
+	}
+	return imports, usesDate
+}
+
+// isExternalType reports whether a component schema maps to a type defined outside the
+// generated code through x-go-type, so no type is generated for it.
+func isExternalType(schema *openapi3.Schema) bool {
+	_, ok := customGoType(schema)
+	return ok
+}
+
+// isStandardLibrary reports whether an import path belongs to the standard library.
+func isStandardLibrary(importPath string) bool {
+	return !strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".")
+}
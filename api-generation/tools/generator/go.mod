@@ -4,7 +4,10 @@ go 1.22.5
 
 toolchain go1.24.4
 
-require github.com/getkin/kin-openapi v0.130.0
+require (
+	github.com/getkin/kin-openapi v0.130.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/go-openapi/jsonpointer v0.21.0 // indirect
@@ -15,5 +18,4 @@ require (
 	github.com/oasdiff/yaml v0.0.0-20250309154309-f31be36b4037 // indirect
 	github.com/oasdiff/yaml3 v0.0.0-20250309153720-d2182401db90 // indirect
 	github.com/perimeterx/marshmallow v1.1.5 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -1,33 +1,51 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-	"text/template"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
 type Field struct {
-	Name    string
-	Type    string
-	JSONTag string
-	Comment string
+	Name        string
+	Type        string
+	JSONName    string
+	JSONTag     string
+	Comment     string
+	Validations []Validation
+	Items       []Validation      // Checks on each element of a slice, rendered on "item"
+	Nested      *NestedValidation // Validation of a value that has its own Validate method
 }
 
 type TypeDef struct {
-	Name        string
-	Description string
-	Fields      []Field
+	Name           string
+	Description    string
+	Fields         []Field
+	UnionFields    []UnionField      // Fields decoded through a union's discriminator
+	Memberships    []UnionMembership // Unions this struct is a variant of
+	ErrorCode      string            // Stable code when the struct is an error response body
+	ErrorCodeConst string
+	HasMessage     bool // Error types with a "message" property use it as their error text
 }
 
 type TypeAlias struct {
 	Name         string
 	Type         string
 	OriginalName string
+	Constants    []EnumValue // Re-exported enum constants for aliases of shared enum types
+	Funcs        []Reexport  // Re-exported functions for aliases of shared union types
+}
+
+// Reexport is a package-level function re-exported from the common package
+type Reexport struct {
+	Name   string
+	Target string
 }
 
 type Method struct {
@@ -36,19 +54,45 @@ type Method struct {
 	HasRequest  bool
 	RequestType string
 	ReturnType  string
+	Errors      []MethodError // Error responses declared for the operation
 }
 
 type ActorInterface struct {
-	ActorType     string
-	InterfaceName string
-	InterfaceDesc string
-	Methods       []Method
+	ActorType          string
+	Implementation     string // Struct instantiated by NewActorFactory
+	InterfaceName      string
+	InterfaceDesc      string
+	Methods            []Method
+	Errors             []MethodError // Distinct error types declared by the methods
+	Reminders          []Scheduled
+	Timers             []Scheduled
+	ActorIDValidations []Validation
+	ActorIDPattern     *Pattern
+}
+
+// Callbacks returns the reminder and timer handlers the actor implements.
+func (a ActorInterface) Callbacks() []Scheduled {
+	return append(append([]Scheduled{}, a.Reminders...), a.Timers...)
+}
+
+// CommonPackage describes the package holding schemas shared between actor types
+type CommonPackage struct {
+	Name       string
+	ImportPath string
 }
 
 type TypesTemplateData struct {
-	PackageName string
-	Types       []TypeDef
-	TypeAliases []TypeAlias
+	PackageName   string
+	Common        *CommonPackage
+	Imports       []string
+	ModuleImports []string
+	Types         []TypeDef
+	TypeAliases   []TypeAlias
+	Enums         []EnumDef
+	Unions        []UnionDef
+	Patterns      []Pattern
+	Date          bool // Generate the Date type used for format: date
+	Nested        bool // Generate nestedValidationError for Validate methods that validate nested values
 }
 
 type InterfaceTemplateData struct {
@@ -58,68 +102,113 @@ type InterfaceTemplateData struct {
 
 type SingleActorTemplateData struct {
 	PackageName string
+	Imports     []string
 	Actor       ActorInterface
 }
 
+// httpMethods lists the operations of a path item in the order they are processed
+var httpMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
+
 func main() {
-	if len(os.Args) < 3 {
-		log.Fatal("Usage: generator <openapi-file> <base-output-dir>")
+	// Code-first mode: derive the OpenAPI spec from Go actor implementations
+	if len(os.Args) > 1 && os.Args[1] == "spec" {
+		if err := runSpecCommand(os.Args[2:]); err != nil {
+			log.Fatalf("Failed to generate OpenAPI spec: %v", err)
+		}
+		return
+	}
+
+	// Compatibility check between two versions of a spec
+	if len(os.Args) > 1 && os.Args[1] == "compat" {
+		breaking, err := runCompatCommand(os.Args[2:])
+		if err != nil {
+			log.Fatalf("Failed to compare OpenAPI specs: %v", err)
+		}
+		if breaking {
+			os.Exit(1)
+		}
+		return
 	}
 
-	schemaFile := os.Args[1]
-	baseOutputDir := os.Args[2]
+	check := flag.Bool("check", false, "compare generated code with the files on disk instead of writing it; exit non-zero on drift")
+	configFile := flag.String("config", "", "generator config file (YAML) setting specs, output layout, packages and artefacts")
+	flag.StringVar(&templateDir, "templates", "", "directory with templates that replace the embedded templates of the same name")
+	typeScriptDir := flag.String("typescript", "", "also generate a TypeScript client into this directory")
+	flag.Parse()
 
-	// Load OpenAPI spec
-	loader := openapi3.NewLoader()
-	doc, err := loader.LoadFromFile(schemaFile)
+	var config *Config
+	switch {
+	case *configFile != "" && flag.NArg() == 0:
+		var err error
+		config, err = loadConfig(*configFile)
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+	case *configFile == "" && flag.NArg() >= 2:
+		config = &Config{Specs: flag.Args()[:flag.NArg()-1], Output: flag.Arg(flag.NArg() - 1)}
+	default:
+		log.Fatal("Usage: generator [--check] [--templates <dir>] [--typescript <dir>] <openapi-file-or-dir>... <base-output-dir>\n       generator [--check] [--templates <dir>] [--typescript <dir>] --config <config-file>\n       generator spec <go-package-dir> [output-file]\n       generator compat [--format text|json] <old-openapi-file-or-dir> <new-openapi-file-or-dir>\n       generator compat [--format text|json] --config <config-file> <old-config-dir>")
+	}
+
+	if templateDir == "" && config.Templates != "" {
+		templateDir = config.path(config.Templates)
+	}
+	if *typeScriptDir != "" {
+		config.TypeScript = *typeScriptDir
+	}
+
+	// Load OpenAPI spec, merging multiple files and resolving external $refs
+	doc, err := loadSpecs(config.specPaths())
 	if err != nil {
 		log.Fatalf("Failed to load OpenAPI spec: %v", err)
 	}
 
 	// Generate actor-specific packages
-	err = generateActorPackages(doc, baseOutputDir)
+	out := &outputWriter{check: *check}
+	err = generateActorPackages(doc, config, out)
 	if err != nil {
 		log.Fatalf("Failed to generate actor packages: %v", err)
 	}
+
+	if out.check {
+		if len(out.drifted) > 0 {
+			fmt.Printf("%d generated file(s) out of date; re-run the generator without --check\n", len(out.drifted))
+			os.Exit(1)
+		}
+		fmt.Println("Generated code is up to date")
+	}
 }
 
-func generateActorPackages(doc *openapi3.T, baseOutputDir string) error {
+func generateActorPackages(doc *openapi3.T, config *Config, out *outputWriter) error {
 	// Get all actor types
 	actorTypes := getActorTypes(doc)
-	
+
 	if len(actorTypes) == 0 {
 		return fmt.Errorf("no actor types found in OpenAPI specification")
 	}
+	for _, actorType := range sortedKeys(config.Actors) {
+		if !contains(actorTypes, actorType) {
+			return fmt.Errorf("config actors.%s: no such actor type in OpenAPI specification", actorType)
+		}
+	}
 
 	// Group methods by actor type
 	actorMethodsMap := make(map[string][]Method)
-	
-	for path, pathItem := range doc.Paths.Map() {
-		// Process all HTTP methods in the path
-		operations := map[string]*openapi3.Operation{
-			"GET":    pathItem.Get,
-			"POST":   pathItem.Post,
-			"PUT":    pathItem.Put,
-			"DELETE": pathItem.Delete,
-			"PATCH":  pathItem.Patch,
-		}
 
-		for httpMethod, op := range operations {
+	// Paths are visited in sorted order so methods are generated in a stable order
+	paths := doc.Paths.Map()
+	for _, path := range sortedKeys(paths) {
+		pathItem := paths[path]
+
+		// Process all HTTP methods in the path
+		for _, httpMethod := range httpMethods {
+			op := pathItem.GetOperation(httpMethod)
 			if op == nil {
 				continue
 			}
 
 			// Find which actor type this operation belongs to
-			var operationActorType string
-			if op.Tags != nil {
-				for _, tag := range op.Tags {
-					if strings.HasPrefix(tag, "ActorType:") {
-						operationActorType = strings.TrimPrefix(tag, "ActorType:")
-						break
-					}
-				}
-			}
-			
+			operationActorType := getOperationActorType(op)
 			if operationActorType == "" {
 				continue // Skip operations without actor type
 			}
@@ -129,95 +218,286 @@ func generateActorPackages(doc *openapi3.T, baseOutputDir string) error {
 			if err != nil {
 				return fmt.Errorf("failed to extract method from operation %s %s: %v", httpMethod, path, err)
 			}
-			
+
 			actorMethodsMap[operationActorType] = append(actorMethodsMap[operationActorType], *method)
 		}
 	}
 
+	// Work out which schemas each actor uses; schemas used by several actors go to a common package
+	actorSchemas := make(map[string]map[string]bool)
+	actorSchedules := make(map[string]ActorSchedules)
+	usageCount := make(map[string]int)
+	for _, actorType := range actorTypes {
+		if len(actorMethodsMap[actorType]) == 0 {
+			continue
+		}
+		actorSchemas[actorType] = getActorSchemaNames(doc, actorType)
+
+		// Reminder and timer payloads belong to the actor like request bodies do
+		schedules, err := getActorSchedules(doc, actorType)
+		if err != nil {
+			return err
+		}
+		actorSchedules[actorType] = schedules
+		for _, payloadRef := range schedules.payloadRefs() {
+			collectSchemaRefs(doc, payloadRef, actorSchemas[actorType])
+		}
+		for name := range actorSchemas[actorType] {
+			usageCount[name]++
+		}
+	}
+
+	sharedSchemas := make(map[string]bool)
+	for name, count := range usageCount {
+		if count > 1 {
+			sharedSchemas[name] = true
+		}
+	}
+	promoteSharedUnions(doc, sharedSchemas)
+
+	var common *CommonPackage
+	if len(sharedSchemas) > 0 {
+		commonDir := filepath.Join(config.path(config.Output), commonPackageName)
+		importPath, err := config.importPath(commonDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve import path for %s: %v", commonDir, err)
+		}
+
+		err = generateActorTypes(doc, commonPackageName, commonDir, sharedSchemas, nil, nil, out)
+		if err != nil {
+			return fmt.Errorf("failed to generate shared types: %v", err)
+		}
+
+		common = &CommonPackage{Name: commonPackageName, ImportPath: importPath}
+
+		if !out.check {
+			fmt.Printf("Generated shared types package: %s\n", commonDir)
+			fmt.Printf("  %s/types.go\n", commonDir)
+		}
+	}
+
 	// Generate package for each actor type
+	var registryActors []RegistryActor
 	for _, actorType := range actorTypes {
 		methods := actorMethodsMap[actorType]
 		if len(methods) == 0 {
 			continue // Skip actor types with no methods
 		}
 
-		// Create actor-specific package name and directory
-		packageName := strings.ToLower(actorType)
-		if !strings.HasSuffix(packageName, "actor") {
-			packageName += "actor"
-		}
-		
-		outputDir := filepath.Join(baseOutputDir, packageName)
-		
-		// Create output directory
-		err := os.MkdirAll(outputDir, 0755)
-		if err != nil {
-			return fmt.Errorf("failed to create output directory %s: %v", outputDir, err)
-		}
+		// Package name, directory and artefacts come from the config, with defaults derived from the actor type
+		packageName := config.packageName(actorType)
+		outputDir := config.outputDir(actorType)
+		schedules := actorSchedules[actorType]
+		var files []string
 
 		// Generate types for this actor
-		err = generateActorTypes(doc, packageName, outputDir, actorType)
-		if err != nil {
-			return fmt.Errorf("failed to generate types for %s: %v", actorType, err)
+		if config.generates(actorType, artefactTypes) {
+			err := generateActorTypes(doc, packageName, outputDir, actorSchemas[actorType], sharedSchemas, common, out)
+			if err != nil {
+				return fmt.Errorf("failed to generate types for %s: %v", actorType, err)
+			}
+			files = append(files, "types.go")
 		}
 
 		// Generate interface for this actor
-		err = generateActorInterface(doc, packageName, outputDir, actorType, methods)
-		if err != nil {
-			return fmt.Errorf("failed to generate interface for %s: %v", actorType, err)
+		if config.generates(actorType, artefactInterface) {
+			err := generateActorInterface(doc, packageName, outputDir, actorType, methods, schedules, out)
+			if err != nil {
+				return fmt.Errorf("failed to generate interface for %s: %v", actorType, err)
+			}
+			files = append(files, "api.go")
 		}
 
 		// Generate factory for this actor
-		err = generateActorFactory(doc, packageName, outputDir, actorType, methods)
-		if err != nil {
-			return fmt.Errorf("failed to generate factory for %s: %v", actorType, err)
+		if config.generates(actorType, artefactFactory) {
+			err := generateActorFactory(doc, packageName, outputDir, actorType, config.implementation(actorType), methods, schedules, out)
+			if err != nil {
+				return fmt.Errorf("failed to generate factory for %s: %v", actorType, err)
+			}
+			files = append(files, "factory.go")
+			registryActors = append(registryActors, newRegistryActor(doc, actorType, packageName, methods))
+		}
+
+		// Generate typed client for this actor
+		if config.generates(actorType, artefactClient) {
+			err := generateActorClient(doc, packageName, outputDir, actorType, methods, out)
+			if err != nil {
+				return fmt.Errorf("failed to generate client for %s: %v", actorType, err)
+			}
+			files = append(files, "client.go")
+		}
+
+		// Generate mock for unit testing code that depends on this actor
+		if config.generates(actorType, artefactMock) {
+			err := generateActorMock(doc, packageName, outputDir, actorType, methods, schedules, out)
+			if err != nil {
+				return fmt.Errorf("failed to generate mock for %s: %v", actorType, err)
+			}
+			files = append(files, "mock.go")
+		}
+
+		// Generate reminder and timer dispatch for actors that declare them; it extends the factory's server
+		if !schedules.isEmpty() && config.generates(actorType, artefactFactory) {
+			err := generateActorReminders(doc, packageName, outputDir, actorType, methods, schedules, out)
+			if err != nil {
+				return fmt.Errorf("failed to generate reminders for %s: %v", actorType, err)
+			}
+			files = append(files, "reminders.go")
+		}
+
+		// Generate the error envelope for actors that declare error responses, used by the factory and client
+		if hasMethodErrors(methods) && (config.generates(actorType, artefactFactory) || config.generates(actorType, artefactClient)) {
+			err := generateActorErrors(doc, packageName, outputDir, actorType, methods, out)
+			if err != nil {
+				return fmt.Errorf("failed to generate errors for %s: %v", actorType, err)
+			}
+			files = append(files, "errors.go")
+		}
+
+		// Generate the contract test that runs the implementation against the schema examples
+		if config.generates(actorType, artefactContract) {
+			err := generateActorContract(doc, packageName, outputDir, actorType, methods, out)
+			if err != nil {
+				return fmt.Errorf("failed to generate contract test for %s: %v", actorType, err)
+			}
+			files = append(files, "contract_test.go")
+		}
+
+		if !out.check {
+			fmt.Printf("Generated actor package: %s\n", outputDir)
+			for _, file := range files {
+				fmt.Printf("  %s/%s\n", outputDir, file)
+			}
+		}
+	}
+
+	// Generate the registry that wires every actor factory into the Dapr service
+	if len(registryActors) > 0 {
+		if err := generateRegistry(config, registryActors, out); err != nil {
+			return fmt.Errorf("failed to generate registry: %v", err)
 		}
+		if !out.check {
+			fmt.Printf("Generated registry package: %s\n", config.registryDir())
+			fmt.Printf("  %s/registry.go\n", config.registryDir())
+		}
+	}
 
-		fmt.Printf("Generated actor package: %s\n", outputDir)
-		fmt.Printf("  %s/types.go\n", outputDir)
-		fmt.Printf("  %s/api.go\n", outputDir)
-		fmt.Printf("  %s/factory.go\n", outputDir)
+	// Generate the TypeScript client from the same method and type model
+	if config.TypeScript != "" {
+		typeScriptDir := config.path(config.TypeScript)
+		if err := generateTypeScript(doc, typeScriptDir, actorTypes, actorMethodsMap, actorSchemas, out); err != nil {
+			return fmt.Errorf("failed to generate TypeScript client: %v", err)
+		}
+		if !out.check {
+			fmt.Printf("Generated TypeScript client: %s\n", typeScriptDir)
+		}
 	}
 
 	return nil
 }
 
-func generateActorTypes(doc *openapi3.T, packageName, outputDir, actorType string) error {
-	// Parse types from schemas - for now, include all types in each actor package
-	// In the future, we could filter types based on usage by each actor
-	types := []TypeDef{}
+// buildTypes builds the type model of the component schemas in schemaNames, which the Go and
+// TypeScript targets render. Schemas that are also in shared become aliases of the types in common.
+func buildTypes(doc *openapi3.T, schemaNames, shared map[string]bool, common *CommonPackage) (*structBuilder, []TypeAlias, error) {
+	if err := checkOptionalModes(doc); err != nil {
+		return nil, nil, err
+	}
+	memberships, err := collectUnionMemberships(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	errorSchemas, err := collectErrorSchemas(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	builder := &structBuilder{memberships: memberships, errorSchemas: errorSchemas}
 	typeAliases := []TypeAlias{}
 
-	if doc.Components != nil && doc.Components.Schemas != nil {
-		for name, schemaRef := range doc.Components.Schemas {
-			schema := schemaRef.Value
-			if schema.Type.Is("object") && schema.Properties != nil {
-				// Generate struct type
-				fields := []Field{}
-				for propName, propRef := range schema.Properties {
-					prop := propRef.Value
-					goType := getGoType(prop)
-					jsonTag := propName
-					if !contains(schema.Required, propName) {
-						jsonTag += ",omitempty"
-					}
-					fields = append(fields, Field{
-						Name:    capitalizeFirst(propName),
-						Type:    goType,
-						JSONTag: jsonTag,
-						Comment: prop.Description,
+	if doc.Components == nil {
+		return builder, typeAliases, nil
+	}
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		schemaRef := doc.Components.Schemas[name]
+		if !schemaNames[name] {
+			continue // Not referenced by this package's operations
+		}
+		if isExternalType(schemaRef.Value) {
+			continue // Mapped to an existing Go type with x-go-type
+		}
+
+		if shared[name] {
+			alias := TypeAlias{
+				Name:         name,
+				Type:         common.Name + "." + name,
+				OriginalName: name,
+			}
+			if isEnum(schemaRef.Value) {
+				for _, value := range enumValues(name, schemaRef.Value) {
+					alias.Constants = append(alias.Constants, EnumValue{
+						ConstName: value.ConstName,
+						Literal:   common.Name + "." + value.ConstName,
 					})
 				}
-				types = append(types, TypeDef{
-					Name:        name,
-					Description: schema.Description,
-					Fields:      fields,
+			}
+			if errorSchema, ok := errorSchemas[name]; ok {
+				alias.Constants = append(alias.Constants, EnumValue{
+					ConstName: errorSchema.CodeConst,
+					Literal:   common.Name + "." + errorSchema.CodeConst,
 				})
 			}
+			if isUnion(schemaRef.Value) {
+				for _, fn := range []string{"Unmarshal" + name, "Unmarshal" + name + "As"} {
+					alias.Funcs = append(alias.Funcs, Reexport{Name: fn, Target: common.Name + "." + fn})
+				}
+			}
+			typeAliases = append(typeAliases, alias)
+			continue
+		}
+
+		schema := schemaRef.Value
+		if schema.Type.Is("object") && schema.Properties != nil {
+			// Generate struct type (and named types for any inline nested objects)
+			builder.addStruct(name, schema)
+		} else if isEnum(schema) {
+			// Generate named type with constants for closed value sets
+			builder.addEnum(name, schema)
+		} else if isUnion(schema) {
+			// Generate sealed interface decoded by discriminator
+			union, err := buildUnion(name, schema)
+			if err != nil {
+				return nil, nil, err
+			}
+			builder.unions = append(builder.unions, union)
+		} else if len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+			// Composition without a discriminator cannot be decoded into a concrete type
+			typeAliases = append(typeAliases, TypeAlias{
+				Name:         name,
+				Type:         "interface{}",
+				OriginalName: name,
+			})
 		}
+	}
 
+	return builder, typeAliases, nil
+}
+
+// generateActorTypes writes types.go for the schemas in schemaNames. Schemas that are also in
+// shared are emitted as aliases of the types in the common package instead of being redefined.
+func generateActorTypes(doc *openapi3.T, packageName, outputDir string, schemaNames, shared map[string]bool, common *CommonPackage, out *outputWriter) error {
+	builder, typeAliases, err := buildTypes(doc, schemaNames, shared, common)
+	if err != nil {
+		return err
+	}
+
+	if doc.Components != nil && doc.Components.Schemas != nil {
 		// Generate type aliases for parameter types
-		for _, pathItem := range doc.Paths.Map() {
+		paths := doc.Paths.Map()
+		for _, path := range sortedKeys(paths) {
+			pathItem := paths[path]
+			if packageName == commonPackageName {
+				break // Parameter aliases belong to the actor packages
+			}
 			for _, param := range pathItem.Parameters {
 				p := param.Value
 				if p.Schema != nil && p.Schema.Value.Type.Is("string") {
@@ -232,9 +512,8 @@ func generateActorTypes(doc *openapi3.T, packageName, outputDir, actorType strin
 		}
 	}
 
-	// Load template from file
-	templatePath := getTemplatePath("types.tmpl")
-	tmpl, err := template.ParseFiles(templatePath)
+	// Load the embedded template, or its --templates override
+	tmpl, err := loadTemplate("types.tmpl")
 	if err != nil {
 		return fmt.Errorf("failed to parse types template: %v", err)
 	}
@@ -242,99 +521,200 @@ func generateActorTypes(doc *openapi3.T, packageName, outputDir, actorType strin
 	// Generate types file
 	data := TypesTemplateData{
 		PackageName: packageName,
-		Types:       types,
+		Common:      common,
+		Types:       builder.types,
 		TypeAliases: typeAliases,
+		Enums:       builder.enums,
+		Unions:      builder.unions,
+		Patterns:    builder.patterns,
+		Date:        builder.usesDate,
+		Nested:      builder.nestedValidation,
 	}
 
-	typesFile, err := os.Create(fmt.Sprintf("%s/types.go", outputDir))
-	if err != nil {
-		return fmt.Errorf("failed to create types file: %v", err)
+	// ValidationError is defined in the common package when there is one, otherwise locally
+	if common != nil {
+		data.ModuleImports = append(data.ModuleImports, common.ImportPath)
+	} else {
+		data.Imports = append(data.Imports, "fmt")
 	}
-	defer typesFile.Close()
-
-	err = tmpl.Execute(typesFile, data)
-	if err != nil {
-		return fmt.Errorf("failed to execute types template: %v", err)
+	if builder.needsJSON() {
+		data.Imports = append(data.Imports, "encoding/json")
+	}
+	if common != nil && (builder.needsJSON() || builder.indexedValidation) {
+		data.Imports = append(data.Imports, "fmt")
 	}
+	data.Imports = append(data.Imports, validationImports(builder.validations)...)
+	for _, importPath := range sortedKeys(builder.imports) {
+		if isStandardLibrary(importPath) {
+			data.Imports = append(data.Imports, importPath)
+		} else {
+			data.ModuleImports = append(data.ModuleImports, importPath)
+		}
+	}
+	sort.Strings(data.Imports)
+	sort.Strings(data.ModuleImports)
 
-	return nil
+	return out.writeGoFile(filepath.Join(outputDir, "types.go"), tmpl, data)
 }
 
-func generateActorInterface(doc *openapi3.T, packageName, outputDir, actorType string, methods []Method) error {
-	// Load template from file
-	templatePath := getTemplatePath("interface.tmpl")
-	tmpl, err := template.ParseFiles(templatePath)
+func generateActorInterface(doc *openapi3.T, packageName, outputDir, actorType string, methods []Method, schedules ActorSchedules, out *outputWriter) error {
+	// Load the embedded template, or its --templates override
+	tmpl, err := loadTemplate("interface.tmpl")
 	if err != nil {
 		return fmt.Errorf("failed to parse interface template: %v", err)
 	}
 
 	interfaceName := actorType + "API"
 	interfaceDesc := fmt.Sprintf("defines the interface that must be implemented to satisfy the OpenAPI schema for %s", actorType)
-	
+
 	actor := ActorInterface{
 		ActorType:     actorType,
 		InterfaceName: interfaceName,
 		InterfaceDesc: interfaceDesc,
 		Methods:       methods,
+		Reminders:     schedules.Reminders,
+		Timers:        schedules.Timers,
+	}
+
+	// Validate actor IDs against the actorId path parameter schema
+	if idSchema := getActorIDSchema(doc, actorType); idSchema != nil {
+		actor.ActorIDValidations, actor.ActorIDPattern = buildValidations(idSchema, "id", "string", "actorIDPattern")
 	}
 
 	// Generate interface file for this actor
 	data := SingleActorTemplateData{
 		PackageName: packageName,
+		Imports:     validationImports(actor.ActorIDValidations),
 		Actor:       actor,
 	}
 
 	// Use api.go as filename instead of generated.go for better clarity
-	interfaceFile, err := os.Create(filepath.Join(outputDir, "api.go"))
+	return out.writeGoFile(filepath.Join(outputDir, "api.go"), tmpl, data)
+}
+
+func generateActorFactory(doc *openapi3.T, packageName, outputDir, actorType, implementation string, methods []Method, schedules ActorSchedules, out *outputWriter) error {
+	// Load the embedded template, or its --templates override
+	tmpl, err := loadTemplate("factory.tmpl")
 	if err != nil {
-		return fmt.Errorf("failed to create interface file: %v", err)
+		return fmt.Errorf("failed to parse factory template: %v", err)
 	}
-	defer interfaceFile.Close()
 
-	err = tmpl.Execute(interfaceFile, data)
+	interfaceName := actorType + "API"
+	interfaceDesc := fmt.Sprintf("defines the interface that must be implemented to satisfy the OpenAPI schema for %s", actorType)
+
+	actor := ActorInterface{
+		ActorType:      actorType,
+		InterfaceName:  interfaceName,
+		InterfaceDesc:  interfaceDesc,
+		Implementation: implementation,
+		Methods:        methods,
+		Errors:         actorErrorTypes(methods),
+		Reminders:      schedules.Reminders,
+		Timers:         schedules.Timers,
+	}
+
+	// Generate factory file for this actor
+	data := SingleActorTemplateData{
+		PackageName: packageName,
+		Actor:       actor,
+	}
+
+	return out.writeGoFile(filepath.Join(outputDir, "factory.go"), tmpl, data)
+}
+
+func generateActorClient(doc *openapi3.T, packageName, outputDir, actorType string, methods []Method, out *outputWriter) error {
+	// Load the embedded template, or its --templates override
+	tmpl, err := loadTemplate("client.tmpl")
 	if err != nil {
-		return fmt.Errorf("failed to execute interface template: %v", err)
+		return fmt.Errorf("failed to parse client template: %v", err)
 	}
 
-	return nil
+	actor := ActorInterface{
+		ActorType:     actorType,
+		InterfaceName: actorType + "API",
+		Methods:       methods,
+		Errors:        actorErrorTypes(methods),
+	}
+
+	// Generate client file for this actor
+	data := SingleActorTemplateData{
+		PackageName: packageName,
+		Actor:       actor,
+	}
+
+	return out.writeGoFile(filepath.Join(outputDir, "client.go"), tmpl, data)
 }
 
-func generateActorFactory(doc *openapi3.T, packageName, outputDir, actorType string, methods []Method) error {
-	// Load template from file
-	templatePath := getTemplatePath("factory.tmpl")
-	tmpl, err := template.ParseFiles(templatePath)
+func generateActorMock(doc *openapi3.T, packageName, outputDir, actorType string, methods []Method, schedules ActorSchedules, out *outputWriter) error {
+	// Load the embedded template, or its --templates override
+	tmpl, err := loadTemplate("mock.tmpl")
 	if err != nil {
-		return fmt.Errorf("failed to parse factory template: %v", err)
+		return fmt.Errorf("failed to parse mock template: %v", err)
 	}
 
-	interfaceName := actorType + "API"
-	interfaceDesc := fmt.Sprintf("defines the interface that must be implemented to satisfy the OpenAPI schema for %s", actorType)
-	
 	actor := ActorInterface{
 		ActorType:     actorType,
-		InterfaceName: interfaceName,
-		InterfaceDesc: interfaceDesc,
+		InterfaceName: actorType + "API",
 		Methods:       methods,
+		Reminders:     schedules.Reminders,
+		Timers:        schedules.Timers,
 	}
 
-	// Generate factory file for this actor
+	// Generate mock file for this actor
 	data := SingleActorTemplateData{
 		PackageName: packageName,
 		Actor:       actor,
 	}
 
-	factoryFile, err := os.Create(filepath.Join(outputDir, "factory.go"))
+	return out.writeGoFile(filepath.Join(outputDir, "mock.go"), tmpl, data)
+}
+
+func generateActorReminders(doc *openapi3.T, packageName, outputDir, actorType string, methods []Method, schedules ActorSchedules, out *outputWriter) error {
+	// Load the embedded template, or its --templates override
+	tmpl, err := loadTemplate("reminders.tmpl")
 	if err != nil {
-		return fmt.Errorf("failed to create factory file: %v", err)
+		return fmt.Errorf("failed to parse reminders template: %v", err)
 	}
-	defer factoryFile.Close()
 
-	err = tmpl.Execute(factoryFile, data)
+	actor := ActorInterface{
+		ActorType:     actorType,
+		InterfaceName: actorType + "API",
+		Methods:       methods,
+		Reminders:     schedules.Reminders,
+		Timers:        schedules.Timers,
+	}
+
+	// Generate reminders file for this actor
+	data := SingleActorTemplateData{
+		PackageName: packageName,
+		Imports:     schedules.imports(),
+		Actor:       actor,
+	}
+
+	return out.writeGoFile(filepath.Join(outputDir, "reminders.go"), tmpl, data)
+}
+
+func generateActorErrors(doc *openapi3.T, packageName, outputDir, actorType string, methods []Method, out *outputWriter) error {
+	// Load the embedded template, or its --templates override
+	tmpl, err := loadTemplate("errors.tmpl")
 	if err != nil {
-		return fmt.Errorf("failed to execute factory template: %v", err)
+		return fmt.Errorf("failed to parse errors template: %v", err)
 	}
 
-	return nil
+	actor := ActorInterface{
+		ActorType:     actorType,
+		InterfaceName: actorType + "API",
+		Methods:       methods,
+		Errors:        actorErrorTypes(methods),
+	}
+
+	// Generate errors file for this actor
+	data := SingleActorTemplateData{
+		PackageName: packageName,
+		Actor:       actor,
+	}
+
+	return out.writeGoFile(filepath.Join(outputDir, "errors.go"), tmpl, data)
 }
 
 // extractMethodFromOperation extracts method information from OpenAPI operation
@@ -344,7 +724,7 @@ func extractMethodFromOperation(op *openapi3.Operation, httpMethod, path string)
 	if methodName == "" {
 		return nil, fmt.Errorf("failed to extract method name from path '%s': path must follow pattern '/{actorId}/method/{methodName}'", path)
 	}
-	
+
 	// Capitalize the method name for Go interface (exported method)
 	methodName = strings.Title(methodName)
 
@@ -369,6 +749,9 @@ func extractMethodFromOperation(op *openapi3.Operation, httpMethod, path string)
 		method.ReturnType = returnType
 	}
 
+	// Error responses become typed errors carried in an ErrorEnvelope
+	method.Errors = operationErrors(op)
+
 	return method, nil
 }
 
@@ -385,7 +768,6 @@ func extractMethodNameFromPath(path string) string {
 	return ""
 }
 
-
 // getOperationComment extracts comment from operation summary/description
 func getOperationComment(op *openapi3.Operation) string {
 	if op.Summary != "" {
@@ -404,7 +786,7 @@ func extractRequestType(requestBody *openapi3.RequestBody) string {
 	if requestBody.Content == nil {
 		return ""
 	}
-	
+
 	// Look for JSON content
 	if jsonContent := requestBody.Content.Get("application/json"); jsonContent != nil {
 		if jsonContent.Schema != nil && jsonContent.Schema.Ref != "" {
@@ -415,7 +797,7 @@ func extractRequestType(requestBody *openapi3.RequestBody) string {
 			}
 		}
 	}
-	
+
 	return ""
 }
 
@@ -424,13 +806,13 @@ func extractReturnType(op *openapi3.Operation) string {
 	if op.Responses == nil {
 		return ""
 	}
-	
+
 	// Look for 200 response
 	response200 := op.Responses.Status(200)
 	if response200 == nil || response200.Value == nil || response200.Value.Content == nil {
 		return ""
 	}
-	
+
 	// Look for JSON content
 	if jsonContent := response200.Value.Content.Get("application/json"); jsonContent != nil {
 		if jsonContent.Schema != nil && jsonContent.Schema.Ref != "" {
@@ -441,25 +823,62 @@ func extractReturnType(op *openapi3.Operation) string {
 			}
 		}
 	}
-	
+
+	return ""
+}
+
+// getActorIDSchema returns the schema of the actorId path parameter used by the actor's operations
+func getActorIDSchema(doc *openapi3.T, actorType string) *openapi3.Schema {
+	paths := doc.Paths.Map()
+	for _, path := range sortedKeys(paths) {
+		pathItem := paths[path]
+		operations := []*openapi3.Operation{
+			pathItem.Get, pathItem.Post, pathItem.Put, pathItem.Delete, pathItem.Patch,
+		}
+
+		for _, op := range operations {
+			if op == nil || !contains(op.Tags, "ActorType:"+actorType) {
+				continue
+			}
+
+			params := append(openapi3.Parameters{}, pathItem.Parameters...)
+			params = append(params, op.Parameters...)
+			for _, param := range params {
+				p := param.Value
+				if p != nil && p.In == "path" && p.Name == "actorId" && p.Schema != nil {
+					return p.Schema.Value
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getOperationActorType returns the actor type named by an operation's "ActorType:" tag, or "" if it has none
+func getOperationActorType(op *openapi3.Operation) string {
+	for _, tag := range op.Tags {
+		if strings.HasPrefix(tag, "ActorType:") {
+			return strings.TrimPrefix(tag, "ActorType:")
+		}
+	}
 	return ""
 }
 
 // getActorTypes extracts all actor types from OpenAPI spec
 func getActorTypes(doc *openapi3.T) []string {
 	actorTypeSet := make(map[string]bool)
-	
+
 	// Extract from tags in operations (e.g., "ActorType:CounterActor")
 	for _, pathItem := range doc.Paths.Map() {
 		operations := []*openapi3.Operation{
 			pathItem.Get, pathItem.Post, pathItem.Put, pathItem.Delete, pathItem.Patch,
 		}
-		
+
 		for _, op := range operations {
 			if op == nil || op.Tags == nil {
 				continue
 			}
-			
+
 			for _, tag := range op.Tags {
 				if strings.HasPrefix(tag, "ActorType:") {
 					actorType := strings.TrimPrefix(tag, "ActorType:")
@@ -470,13 +889,10 @@ func getActorTypes(doc *openapi3.T) []string {
 			}
 		}
 	}
-	
-	// Convert set to slice
-	var actorTypes []string
-	for actorType := range actorTypeSet {
-		actorTypes = append(actorTypes, actorType)
-	}
-	
+
+	// Convert set to sorted slice
+	actorTypes := sortedKeys(actorTypeSet)
+
 	// Fallback if no actor types found
 	if len(actorTypes) == 0 {
 		if doc.Info != nil && doc.Info.Title != "" {
@@ -494,7 +910,7 @@ func getActorTypes(doc *openapi3.T) []string {
 			actorTypes = append(actorTypes, "Actor")
 		}
 	}
-	
+
 	return actorTypes
 }
 
@@ -528,57 +944,24 @@ func toSnakeCase(s string) string {
 	return strings.ToLower(result.String())
 }
 
-
-
-func getTemplatePath(templateName string) string {
-	// Get the directory where this binary is located
-	execPath, err := os.Executable()
-	if err != nil {
-		log.Fatalf("Failed to get executable path: %v", err)
-	}
-	
-	// Look for templates directory relative to the executable
-	execDir := filepath.Dir(execPath)
-	templatePath := filepath.Join(execDir, "..", "templates", templateName)
-	
-	// If not found, try relative to current working directory (for development)
-	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
-		wd, _ := os.Getwd()
-		templatePath = filepath.Join(wd, "templates", templateName)
-		
-		// If still not found, try relative to the generator source directory
-		if _, err := os.Stat(templatePath); os.IsNotExist(err) {
-			// Try to find the templates directory in the project structure
-			// Walk up from the executable to find api-generation/tools/generator/templates
-			currentDir := execDir
-			for i := 0; i < 10; i++ { // Limit search depth
-				testPath := filepath.Join(currentDir, "generator", "templates", templateName)
-				if _, err := os.Stat(testPath); err == nil {
-					templatePath = testPath
-					break
-				}
-				testPath = filepath.Join(currentDir, "tools", "generator", "templates", templateName)
-				if _, err := os.Stat(testPath); err == nil {
-					templatePath = testPath
-					break
-				}
-				testPath = filepath.Join(currentDir, "api-generation", "tools", "generator", "templates", templateName)
-				if _, err := os.Stat(testPath); err == nil {
-					templatePath = testPath
-					break
-				}
-				currentDir = filepath.Dir(currentDir)
-				if currentDir == "/" || currentDir == filepath.Dir(currentDir) {
-					break
-				}
-			}
+// getGoType maps a schema to a Go type. Component $refs resolve to their named type (or the
+// x-go-type they declare), string formats to their rich types, and arrays and maps carry their
+// element types.
+func getGoType(schemaRef *openapi3.SchemaRef) string {
+	if schemaRef == nil || schemaRef.Value == nil && schemaRef.Ref == "" {
+		return "interface{}"
+	}
+	if schemaRef.Ref != "" {
+		if ref, ok := customGoType(schemaRef.Value); ok {
+			return ref.Type
 		}
+		return refName(schemaRef.Ref)
 	}
-	
-	return templatePath
-}
 
-func getGoType(schema *openapi3.Schema) string {
+	schema := schemaRef.Value
+	if ref, ok := formatGoType(schema); ok {
+		return ref.Type
+	}
 	switch {
 	case schema.Type.Is("string"):
 		return "string"
@@ -586,6 +969,9 @@ func getGoType(schema *openapi3.Schema) string {
 		if schema.Format == "int32" {
 			return "int32"
 		}
+		if schema.Format == "int64" {
+			return "int64"
+		}
 		return "int"
 	case schema.Type.Is("number"):
 		if schema.Format == "float" {
@@ -596,10 +982,13 @@ func getGoType(schema *openapi3.Schema) string {
 		return "bool"
 	case schema.Type.Is("array"):
 		if schema.Items != nil {
-			return "[]" + getGoType(schema.Items.Value)
+			return "[]" + getGoType(schema.Items)
 		}
 		return "[]interface{}"
 	case schema.Type.Is("object"):
+		if schema.AdditionalProperties.Schema != nil {
+			return "map[string]" + getGoType(schema.AdditionalProperties.Schema)
+		}
 		if schema.AdditionalProperties.Has != nil && *schema.AdditionalProperties.Has {
 			return "map[string]interface{}"
 		}
@@ -616,6 +1005,23 @@ func capitalizeFirst(s string) string {
 	return strings.ToUpper(s[:1]) + s[1:]
 }
 
+func lowerFirst(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// sortedKeys returns the keys of m in sorted order, so generated output does not depend on map iteration order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -623,4 +1029,4 @@ func contains(slice []string, item string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
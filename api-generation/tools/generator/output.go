@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// outputWriter renders templates into gofmt'd Go source and other generated files. In check mode it compares the
+// result with the files on disk instead of writing them and records the files that drifted.
+type outputWriter struct {
+	check   bool
+	drifted []string
+}
+
+// writeGoFile executes tmpl with data, formats the result and writes it to path
+// (or, in check mode, reports a diff against the existing file).
+func (w *outputWriter) writeGoFile(path string, tmpl *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute %s template: %v", tmpl.Name(), err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %v", path, err)
+	}
+	return w.writeFile(path, source)
+}
+
+// writeFile writes generated source to path (or, in check mode, reports a diff against the existing file).
+func (w *outputWriter) writeFile(path string, source []byte) error {
+	if w.check {
+		return w.compare(path, source)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, source, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// compare prints a diff between the file on disk and the freshly generated source.
+func (w *outputWriter) compare(path string, generated []byte) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if bytes.Equal(existing, generated) {
+		return nil
+	}
+
+	w.drifted = append(w.drifted, path)
+	if existing == nil {
+		fmt.Printf("%s is missing\n", path)
+		return nil
+	}
+	fmt.Printf("--- %s (checked in)\n+++ %s (generated)\n", path, path)
+	fmt.Print(lineDiff(string(existing), string(generated)))
+	return nil
+}
+
+// lineDiff renders a minimal line-based diff of a and b with a few lines of context
+// around each change. It favours simplicity over speed; generated files are small.
+func lineDiff(a, b string) string {
+	const context = 3
+
+	aLines := strings.SplitAfter(a, "\n")
+	bLines := strings.SplitAfter(b, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of aLines[i:] and bLines[j:]
+	lcs := make([][]int, len(aLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bLines)+1)
+	}
+	for i := len(aLines) - 1; i >= 0; i-- {
+		for j := len(bLines) - 1; j >= 0; j-- {
+			if aLines[i] == bLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(aLines) || j < len(bLines) {
+		switch {
+		case i < len(aLines) && j < len(bLines) && aLines[i] == bLines[j]:
+			lines = append(lines, line{' ', aLines[i]})
+			i++
+			j++
+		case i < len(aLines) && (j == len(bLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', aLines[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', bLines[j]})
+			j++
+		}
+	}
+
+	// Only print changed lines and the context around them
+	var out strings.Builder
+	lastPrinted := -1
+	for k, l := range lines {
+		if l.op == ' ' {
+			near := false
+			for d := max(0, k-context); d <= min(len(lines)-1, k+context); d++ {
+				if lines[d].op != ' ' {
+					near = true
+					break
+				}
+			}
+			if !near {
+				continue
+			}
+		}
+		if lastPrinted >= 0 && k > lastPrinted+1 {
+			out.WriteString("@@\n")
+		}
+		out.WriteByte(l.op)
+		out.WriteString(strings.TrimSuffix(l.text, "\n"))
+		out.WriteByte('\n')
+		lastPrinted = k
+	}
+	return out.String()
+}
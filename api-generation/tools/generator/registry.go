@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// registryPackageName is the default package that registers all generated actor factories.
+const registryPackageName = "registry"
+
+// RegistryActor is an actor type listed in the generated registry.
+type RegistryActor struct {
+	ActorType   string
+	PackageName string
+	Description string
+	Persistence string
+	Methods     []string
+}
+
+type RegistryTemplateData struct {
+	PackageName string
+	Imports     []string // Quoted import specs of the actor packages
+	Actors      []RegistryActor
+}
+
+// newRegistryActor collects the registry metadata of actorType. The description and the
+// x-dapr-persistence extension come from the "ActorType:<name>" tag.
+func newRegistryActor(doc *openapi3.T, actorType, packageName string, methods []Method) RegistryActor {
+	actor := RegistryActor{ActorType: actorType, PackageName: packageName}
+	if tag := doc.Tags.Get("ActorType:" + actorType); tag != nil {
+		actor.Description = tag.Description
+		actor.Persistence = stringValue(tag.Extensions["x-dapr-persistence"])
+	}
+	for _, method := range methods {
+		actor.Methods = append(actor.Methods, method.Name)
+	}
+	return actor
+}
+
+// generateRegistry writes the registry package for the actors whose factories were generated.
+func generateRegistry(config *Config, actors []RegistryActor, out *outputWriter) error {
+	tmpl, err := loadTemplate("registry.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to parse registry template: %v", err)
+	}
+
+	outputDir := config.registryDir()
+	data := RegistryTemplateData{
+		PackageName: filepath.Base(outputDir),
+		Actors:      actors,
+	}
+	for _, actor := range actors {
+		importPath, err := config.importPath(config.outputDir(actor.ActorType))
+		if err != nil {
+			return fmt.Errorf("failed to resolve import path of %s: %v", actor.ActorType, err)
+		}
+		// Packages named differently from their directory are imported with an explicit name
+		if filepath.Base(importPath) != actor.PackageName {
+			importPath = actor.PackageName + " " + strconv.Quote(importPath)
+		} else {
+			importPath = strconv.Quote(importPath)
+		}
+		data.Imports = append(data.Imports, importPath)
+	}
+	sort.Strings(data.Imports)
+
+	return out.writeGoFile(filepath.Join(outputDir, "registry.go"), tmpl, data)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const registrySpec = `openapi: 3.0.3
+info:
+  title: Shop
+  version: 1.0.0
+tags:
+  - name: ActorType:ShopActor
+    description: Shop with a catalogue
+    x-dapr-persistence: State-based
+paths:
+  /ShopActor/{actorId}/method/getItems:
+    get:
+      operationId: getItems
+      tags: [ActorType:ShopActor]
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Items'
+  /ShopActor/{actorId}/method/addItem:
+    post:
+      operationId: addItem
+      tags: [ActorType:ShopActor]
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Items'
+      responses:
+        '200':
+          description: OK
+  /WalletActor/{actorId}/method/getBalance:
+    get:
+      operationId: getBalance
+      tags: [ActorType:WalletActor]
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Balance'
+components:
+  schemas:
+    Items:
+      type: object
+      properties:
+        names:
+          type: array
+          items:
+            type: string
+    Balance:
+      type: object
+      properties:
+        amount:
+          type: number
+`
+
+func TestGenerateRegistry(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		registry string   // Registry file, relative to the config directory
+		want     []string // Substrings of the registry
+		notWant  []string
+	}{
+		{
+			name:     "default registry",
+			config:   Config{Output: "internal"},
+			registry: "internal/registry/registry.go",
+			want: []string{
+				"package registry",
+				`"example.com/shop/internal/shopactor"`,
+				`"example.com/shop/internal/walletactor"`,
+				"ActorType:   shopactor.ActorTypeShopActor,\n" +
+					"\t\tDescription: \"Shop with a catalogue\",\n" +
+					"\t\tPersistence: \"State-based\",\n" +
+					"\t\tMethods:     []string{\"AddItem\", \"GetItems\"},\n" +
+					"\t\tFactory:     shopactor.NewActorFactory(),",
+				"ActorType:   walletactor.ActorTypeWalletActor,\n\t\tDescription: \"\",",
+			},
+		},
+		{
+			name: "package named differently from its directory",
+			config: Config{Output: "internal", Registry: "internal/actors", Actors: map[string]ActorConfig{
+				"WalletActor": {Package: "wallet", Output: "internal/accounts"},
+			}},
+			registry: "internal/actors/registry.go",
+			want: []string{
+				"package actors",
+				`wallet "example.com/shop/internal/accounts"`,
+				"Factory:     wallet.NewActorFactory(),",
+			},
+		},
+		{
+			name: "actor without factory",
+			config: Config{Output: "internal", Actors: map[string]ActorConfig{
+				"WalletActor": {Generate: []string{artefactTypes, artefactInterface, artefactClient}},
+			}},
+			registry: "internal/registry/registry.go",
+			want:     []string{"shopactor.NewActorFactory()"},
+			notWant:  []string{"walletactor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeSpecFiles(t, map[string]string{"shop.yaml": registrySpec})
+			config := tt.config
+			config.Specs = []string{"shop.yaml"}
+			config.Module = "example.com/shop"
+			config.dir = dir
+			if err := config.validate(); err != nil {
+				t.Fatalf("invalid config: %v", err)
+			}
+
+			doc, err := loadSpecs(config.specPaths())
+			if err != nil {
+				t.Fatalf("failed to load spec: %v", err)
+			}
+			if err := generateActorPackages(doc, &config, &outputWriter{}); err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+
+			source, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(tt.registry)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(source), want) {
+					t.Errorf("registry does not contain %q:\n%s", want, source)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(string(source), notWant) {
+					t.Errorf("registry contains %q:\n%s", notWant, source)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Scheduled is a reminder or timer declared with the x-dapr-reminders or x-dapr-timers extension.
+type Scheduled struct {
+	Name        string // Reminder or timer name registered with Dapr
+	GoName      string // Exported Go name (e.g. "MonthlyStatement")
+	Kind        string // "Reminder" or "Timer"
+	Callback    string // Exported callback name: the server method Dapr invokes for a timer, and the mock field prefix (e.g. "OnMonthlyStatementReminder")
+	Handler     string // Unexported implementation method handling it (e.g. "onMonthlyStatementReminder")
+	Description string
+	PayloadType string // Go type of the payload, empty when the callback takes none
+	HasValidate bool   // Payload is a generated struct with a Validate method
+	DueTime     string // Default schedule used when Register is called without one
+	Period      string
+	TTL         string
+
+	payload *openapi3.SchemaRef
+}
+
+// ActorSchedules holds the reminders and timers declared for an actor type.
+type ActorSchedules struct {
+	Reminders []Scheduled
+	Timers    []Scheduled
+}
+
+// getActorSchedules collects the reminders and timers declared for actorType, either on the
+// "ActorType:<name>" tag or on the actor's operations. Each extension is a map keyed by name:
+//
+//	x-dapr-reminders:
+//	  monthlyStatement:
+//	    description: Produces the monthly statement
+//	    period: 720h
+//	    payload:
+//	      $ref: '#/components/schemas/StatementPayload'
+func getActorSchedules(doc *openapi3.T, actorType string) (ActorSchedules, error) {
+	var sources []map[string]interface{}
+	for _, tag := range doc.Tags {
+		if tag.Name == "ActorType:"+actorType {
+			sources = append(sources, tag.Extensions)
+		}
+	}
+	paths := doc.Paths.Map()
+	for _, path := range sortedKeys(paths) {
+		for _, httpMethod := range httpMethods {
+			op := paths[path].GetOperation(httpMethod)
+			if op != nil && getOperationActorType(op) == actorType {
+				sources = append(sources, op.Extensions)
+			}
+		}
+	}
+
+	var schedules ActorSchedules
+	var err error
+	for _, extensions := range sources {
+		if schedules.Reminders, err = parseSchedules(doc, extensions["x-dapr-reminders"], "Reminder", schedules.Reminders); err != nil {
+			return schedules, fmt.Errorf("invalid x-dapr-reminders for %s: %v", actorType, err)
+		}
+		if schedules.Timers, err = parseSchedules(doc, extensions["x-dapr-timers"], "Timer", schedules.Timers); err != nil {
+			return schedules, fmt.Errorf("invalid x-dapr-timers for %s: %v", actorType, err)
+		}
+	}
+
+	sort.Slice(schedules.Reminders, func(i, j int) bool { return schedules.Reminders[i].Name < schedules.Reminders[j].Name })
+	sort.Slice(schedules.Timers, func(i, j int) bool { return schedules.Timers[i].Name < schedules.Timers[j].Name })
+	return schedules, nil
+}
+
+// parseSchedules appends the entries of an x-dapr-reminders/x-dapr-timers value to existing;
+// kind is "Reminder" or "Timer".
+func parseSchedules(doc *openapi3.T, value interface{}, kind string, existing []Scheduled) ([]Scheduled, error) {
+	if value == nil {
+		return existing, nil
+	}
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be a map keyed by name")
+	}
+
+	for _, name := range sortedKeys(entries) {
+		for _, s := range existing {
+			if s.Name == name {
+				return nil, fmt.Errorf("%s is declared more than once", name)
+			}
+		}
+
+		entry, ok := entries[name].(map[string]interface{})
+		if !ok && entries[name] != nil {
+			return nil, fmt.Errorf("%s must be an object", name)
+		}
+
+		scheduled := Scheduled{
+			Name:        name,
+			GoName:      enumConstSuffix(name),
+			Kind:        kind,
+			Callback:    "On" + enumConstSuffix(name) + kind,
+			Handler:     "on" + enumConstSuffix(name) + kind,
+			Description: stringValue(entry["description"]),
+			DueTime:     stringValue(entry["dueTime"]),
+			Period:      stringValue(entry["period"]),
+			TTL:         stringValue(entry["ttl"]),
+		}
+
+		if payload, ok := entry["payload"].(map[string]interface{}); ok {
+			ref, _ := payload["$ref"].(string)
+			schemaRef := doc.Components.Schemas[refName(ref)]
+			if !strings.HasPrefix(ref, "#/components/schemas/") || schemaRef == nil {
+				return nil, fmt.Errorf("payload of %s must be a $ref to a component schema", name)
+			}
+			scheduled.payload = &openapi3.SchemaRef{Ref: ref, Value: schemaRef.Value}
+			scheduled.PayloadType = refName(ref)
+			scheduled.HasValidate = schemaRef.Value.Type.Is("object") && schemaRef.Value.Properties != nil
+		}
+		if scheduled.Description == "" {
+			scheduled.Description = "Handles the " + name + " callback declared in the OpenAPI schema"
+		}
+
+		existing = append(existing, scheduled)
+	}
+	return existing, nil
+}
+
+// payloadRefs returns the payload schemas of all reminders and timers.
+func (s ActorSchedules) payloadRefs() []*openapi3.SchemaRef {
+	var refs []*openapi3.SchemaRef
+	for _, scheduled := range append(append([]Scheduled{}, s.Reminders...), s.Timers...) {
+		if scheduled.payload != nil {
+			refs = append(refs, scheduled.payload)
+		}
+	}
+	return refs
+}
+
+// imports returns the standard library packages used by the generated reminders.go.
+func (s ActorSchedules) imports() []string {
+	imports := []string{"context"}
+	if len(s.payloadRefs()) > 0 {
+		imports = append(imports, "encoding/json")
+	}
+	if len(s.Reminders) > 0 || len(s.payloadRefs()) > 0 {
+		imports = append(imports, "fmt")
+	}
+	if len(s.Reminders) > 0 {
+		imports = append(imports, "log")
+	}
+	return imports
+}
+
+// isEmpty reports whether the actor declares no reminders or timers.
+func (s ActorSchedules) isEmpty() bool {
+	return len(s.Reminders) == 0 && len(s.Timers) == 0
+}
+
+func stringValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// commonPackageName is the package that holds component schemas used by more than one actor type.
+const commonPackageName = "common"
+
+// getActorSchemaNames returns the transitive closure of component schemas referenced by
+// the operations of an actor type (request bodies, responses and parameters).
+func getActorSchemaNames(doc *openapi3.T, actorType string) map[string]bool {
+	used := make(map[string]bool)
+
+	for _, pathItem := range doc.Paths.Map() {
+		operations := []*openapi3.Operation{
+			pathItem.Get, pathItem.Post, pathItem.Put, pathItem.Delete, pathItem.Patch,
+		}
+
+		for _, op := range operations {
+			if op == nil || !contains(op.Tags, "ActorType:"+actorType) {
+				continue
+			}
+
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				for _, mediaType := range op.RequestBody.Value.Content {
+					collectSchemaRefs(doc, mediaType.Schema, used)
+				}
+			}
+
+			if op.Responses != nil {
+				for _, response := range op.Responses.Map() {
+					if response.Value == nil {
+						continue
+					}
+					for _, mediaType := range response.Value.Content {
+						collectSchemaRefs(doc, mediaType.Schema, used)
+					}
+				}
+			}
+
+			for _, param := range op.Parameters {
+				if param.Value != nil {
+					collectSchemaRefs(doc, param.Value.Schema, used)
+				}
+			}
+		}
+	}
+
+	return used
+}
+
+// collectSchemaRefs records every component schema reachable from schemaRef in used.
+func collectSchemaRefs(doc *openapi3.T, schemaRef *openapi3.SchemaRef, used map[string]bool) {
+	if schemaRef == nil {
+		return
+	}
+
+	if schemaRef.Ref != "" {
+		name := refName(schemaRef.Ref)
+		if used[name] {
+			return // Already visited - also guards against recursive schemas
+		}
+		used[name] = true
+	}
+
+	schema := schemaRef.Value
+	if schema == nil {
+		return
+	}
+
+	for _, propRef := range schema.Properties {
+		collectSchemaRefs(doc, propRef, used)
+	}
+	collectSchemaRefs(doc, schema.Items, used)
+	collectSchemaRefs(doc, schema.AdditionalProperties.Schema, used)
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, ref := range refs {
+			collectSchemaRefs(doc, ref, used)
+		}
+	}
+}
+
+// refName extracts the schema name from a $ref (e.g. "#/components/schemas/Foo" -> "Foo")
+func refName(ref string) string {
+	parts := strings.Split(ref, "/")
+	return parts[len(parts)-1]
+}
+
+// resolveImportPath derives the Go import path of dir from the nearest go.mod above it.
+func resolveImportPath(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for current := absDir; ; current = filepath.Dir(current) {
+		modulePath, err := readModulePath(filepath.Join(current, "go.mod"))
+		if err == nil {
+			rel, err := filepath.Rel(current, absDir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return modulePath, nil
+			}
+			return modulePath + "/" + filepath.ToSlash(rel), nil
+		}
+		if current == filepath.Dir(current) {
+			break
+		}
+	}
+
+	return "", fmt.Errorf("no go.mod found above %s", absDir)
+}
+
+// readModulePath returns the module path declared in a go.mod file.
+func readModulePath(goModPath string) (string, error) {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module")), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no module directive in %s", goModPath)
+}
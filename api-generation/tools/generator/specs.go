@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// specExtensions are the file extensions read when a spec argument is a directory.
+var specExtensions = []string{".yaml", ".yml", ".json"}
+
+// schemaDefinition is a component schema together with the file it was found in.
+type schemaDefinition struct {
+	ref  *openapi3.SchemaRef
+	file string
+}
+
+// loadSpecs loads the OpenAPI documents named by paths (files, or directories searched
+// recursively) and merges them into a single document. Files without an "openapi" version are
+// fragments that are only read through relative external $refs, e.g.
+//
+//	$ref: './shared/money.yaml#/components/schemas/Money'
+//
+// Every referenced schema becomes a component of the merged document and references are
+// rewritten to "#/components/schemas/<name>". Schemas with the same name must be identical
+// in all files, and a path may only be declared by one file.
+func loadSpecs(paths []string) (*openapi3.T, error) {
+	files, err := specFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	var merged *openapi3.T
+	definitions := make(map[string][]schemaDefinition)
+	pathOwners := make(map[string]string)
+	for _, file := range files {
+		doc, err := loader.LoadFromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %v", file, err)
+		}
+		if doc.OpenAPI == "" {
+			continue // Fragment, read through the $refs of other files
+		}
+
+		if merged == nil {
+			merged = &openapi3.T{OpenAPI: doc.OpenAPI, Info: doc.Info, Servers: doc.Servers, Paths: openapi3.NewPaths()}
+		}
+		if err := mergeDocument(merged, doc, file, definitions, pathOwners); err != nil {
+			return nil, err
+		}
+	}
+	if merged == nil {
+		return nil, fmt.Errorf("no OpenAPI document found in %s", strings.Join(paths, ", "))
+	}
+
+	merged.Components = &openapi3.Components{Schemas: make(openapi3.Schemas)}
+	for _, name := range sortedKeys(definitions) {
+		defs := definitions[name]
+		for _, def := range defs[1:] {
+			same, err := sameSchema(defs[0].ref.Value, def.ref.Value)
+			if err != nil {
+				return nil, err
+			}
+			if !same {
+				return nil, fmt.Errorf("schema %s is defined differently in %s and %s", name, defs[0].file, def.file)
+			}
+		}
+		merged.Components.Schemas[name] = &openapi3.SchemaRef{Value: defs[0].ref.Value}
+	}
+	return merged, nil
+}
+
+// specFiles expands directories in paths to the spec files they contain, in sorted order.
+func specFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && contains(specExtensions, strings.ToLower(filepath.Ext(file))) {
+				found = append(found, file)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+// mergeDocument adds the paths, tags and schemas of doc, loaded from file, to merged.
+func mergeDocument(merged, doc *openapi3.T, file string, definitions map[string][]schemaDefinition, pathOwners map[string]string) error {
+	collector := &schemaCollector{definitions: definitions, visited: make(map[*openapi3.SchemaRef]bool)}
+
+	if doc.Components != nil {
+		for _, name := range sortedKeys(doc.Components.Schemas) {
+			schemaRef := doc.Components.Schemas[name]
+			collector.add(name, schemaRef, file)
+			collector.walk(schemaRef, file)
+		}
+	}
+
+	paths := doc.Paths.Map()
+	for _, path := range sortedKeys(paths) {
+		if owner, ok := pathOwners[path]; ok {
+			return fmt.Errorf("path %s is declared in both %s and %s", path, owner, file)
+		}
+		pathOwners[path] = file
+		merged.Paths.Set(path, paths[path])
+
+		for _, httpMethod := range httpMethods {
+			if op := paths[path].GetOperation(httpMethod); op != nil {
+				collector.walkOperation(op, paths[path].Parameters, file)
+			}
+		}
+	}
+
+	for _, tag := range doc.Tags {
+		if existing := merged.Tags.Get(tag.Name); existing != nil {
+			if same, err := sameJSON(existing, tag); err != nil || !same {
+				return fmt.Errorf("tag %s is declared differently in %s", tag.Name, file)
+			}
+			continue
+		}
+		merged.Tags = append(merged.Tags, tag)
+	}
+	return nil
+}
+
+// schemaCollector gathers the schemas reachable from a document under their component names
+// and rewrites every $ref to point at the merged components.
+type schemaCollector struct {
+	definitions map[string][]schemaDefinition
+	visited     map[*openapi3.SchemaRef]bool
+}
+
+func (c *schemaCollector) add(name string, schemaRef *openapi3.SchemaRef, file string) {
+	for _, def := range c.definitions[name] {
+		if def.ref.Value == schemaRef.Value {
+			return // Same schema reached through another file
+		}
+	}
+	c.definitions[name] = append(c.definitions[name], schemaDefinition{ref: schemaRef, file: file})
+}
+
+// walkOperation visits the schemas of an operation's parameters, request body and responses.
+func (c *schemaCollector) walkOperation(op *openapi3.Operation, pathParams openapi3.Parameters, file string) {
+	for _, param := range append(append(openapi3.Parameters{}, pathParams...), op.Parameters...) {
+		if param.Value != nil {
+			c.walk(param.Value.Schema, file)
+		}
+	}
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		for _, mediaType := range op.RequestBody.Value.Content {
+			c.walk(mediaType.Schema, file)
+		}
+	}
+	if op.Responses != nil {
+		for _, response := range op.Responses.Map() {
+			if response.Value == nil {
+				continue
+			}
+			for _, mediaType := range response.Value.Content {
+				c.walk(mediaType.Schema, file)
+			}
+		}
+	}
+}
+
+// walk records the schemas referenced from schemaRef, which was found in file, and rewrites
+// their $refs. External refs are resolved relative to the file that contains them.
+func (c *schemaCollector) walk(schemaRef *openapi3.SchemaRef, file string) {
+	if schemaRef == nil || c.visited[schemaRef] {
+		return
+	}
+	c.visited[schemaRef] = true
+
+	if schemaRef.Ref != "" {
+		refFile, name := splitSchemaRef(schemaRef.Ref)
+		if refFile != "" {
+			file = filepath.Join(filepath.Dir(file), refFile)
+		}
+		c.add(name, schemaRef, file)
+		schemaRef.Ref = "#/components/schemas/" + name
+	}
+
+	schema := schemaRef.Value
+	if schema == nil {
+		return
+	}
+	for _, name := range sortedKeys(schema.Properties) {
+		c.walk(schema.Properties[name], file)
+	}
+	c.walk(schema.Items, file)
+	c.walk(schema.AdditionalProperties.Schema, file)
+	c.walk(schema.Not, file)
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, ref := range refs {
+			c.walk(ref, file)
+		}
+	}
+	if schema.Discriminator != nil {
+		for value, mapping := range schema.Discriminator.Mapping {
+			_, name := splitSchemaRef(mapping)
+			schema.Discriminator.Mapping[value] = "#/components/schemas/" + name
+		}
+	}
+}
+
+// splitSchemaRef splits a $ref into the referenced file (empty for local refs) and the schema
+// name. A ref to a whole file is named after the file ("money-amount.yaml" -> "MoneyAmount").
+func splitSchemaRef(ref string) (file, name string) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	if fragment == "" {
+		return file, enumConstSuffix(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+	}
+	return file, refName(fragment)
+}
+
+// sameSchema reports whether two schemas are identical once their $refs have been rewritten.
+func sameSchema(a, b *openapi3.Schema) (bool, error) {
+	if a == b {
+		return true, nil
+	}
+	return sameJSON(a, b)
+}
+
+func sameJSON(a, b interface{}) (bool, error) {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return string(aJSON) == string(bJSON), nil
+}
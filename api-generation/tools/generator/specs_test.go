@@ -0,0 +1,162 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeSpecFiles writes files, keyed by their slash-separated path, into a temporary directory.
+func writeSpecFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// specDocument returns an OpenAPI document with the given paths and component schemas.
+func specDocument(paths, schemas string) string {
+	doc := "openapi: 3.0.3\ninfo:\n  title: T\n  version: 1.0.0\npaths:" + paths + "\n"
+	if schemas != "" {
+		doc += "components:\n  schemas:" + schemas + "\n"
+	}
+	return doc
+}
+
+// specMethod returns a path declaring a POST method of actorType whose request body is schemaRef.
+func specMethod(actorType, method, schemaRef string) string {
+	return `
+  /` + actorType + `/{actorId}/method/` + method + `:
+    post:
+      operationId: ` + method + `
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '` + schemaRef + `'
+      responses:
+        '200':
+          description: OK`
+}
+
+const (
+	moneySchema = `
+    Money:
+      type: object
+      properties:
+        amount:
+          type: number`
+	moneyFragment = "components:\n  schemas:" + moneySchema + "\n"
+)
+
+func TestLoadSpecs(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		paths   []string // Spec arguments, relative to the temporary directory
+		schemas []string
+		methods []string
+		err     string // Substring of the expected error; "" when the specs load
+	}{
+		{
+			name: "external ref to a fragment",
+			files: map[string]string{
+				"wallet.yaml":       specDocument(specMethod("WalletActor", "deposit", "./shared/money.yaml#/components/schemas/Money"), ""),
+				"shared/money.yaml": moneyFragment,
+			},
+			paths:   []string{"."},
+			schemas: []string{"Money"},
+			methods: []string{"/WalletActor/{actorId}/method/deposit"},
+		},
+		{
+			name: "ref to a whole file is named after the file",
+			files: map[string]string{
+				"wallet.yaml":              specDocument(specMethod("WalletActor", "deposit", "./shared/money-amount.yaml"), ""),
+				"shared/money-amount.yaml": "type: number\n",
+			},
+			paths:   []string{"wallet.yaml"},
+			schemas: []string{"MoneyAmount"},
+			methods: []string{"/WalletActor/{actorId}/method/deposit"},
+		},
+		{
+			name: "identical schemas in several files",
+			files: map[string]string{
+				"wallet.yaml":       specDocument(specMethod("WalletActor", "deposit", "#/components/schemas/Money"), moneySchema),
+				"shop.yaml":         specDocument(specMethod("ShopActor", "pay", "./shared/money.yaml#/components/schemas/Money"), ""),
+				"shared/money.yaml": moneyFragment,
+			},
+			paths:   []string{"shop.yaml", "wallet.yaml"},
+			schemas: []string{"Money"},
+			methods: []string{"/ShopActor/{actorId}/method/pay", "/WalletActor/{actorId}/method/deposit"},
+		},
+		{
+			name: "schema defined differently",
+			files: map[string]string{
+				"wallet.yaml": specDocument(specMethod("WalletActor", "deposit", "#/components/schemas/Money"), moneySchema),
+				"shop.yaml": specDocument(specMethod("ShopActor", "pay", "#/components/schemas/Money"),
+					strings.Replace(moneySchema, "type: number", "type: integer", 1)),
+			},
+			paths: []string{"."},
+			err:   "schema Money is defined differently in",
+		},
+		{
+			name: "path declared twice",
+			files: map[string]string{
+				"a.yaml": specDocument(specMethod("WalletActor", "deposit", "#/components/schemas/Money"), moneySchema),
+				"b.yaml": specDocument(specMethod("WalletActor", "deposit", "#/components/schemas/Money"), moneySchema),
+			},
+			paths: []string{"."},
+			err:   "path /WalletActor/{actorId}/method/deposit is declared in both",
+		},
+		{
+			name:  "only fragments",
+			files: map[string]string{"shared/money.yaml": moneyFragment},
+			paths: []string{"."},
+			err:   "no OpenAPI document found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeSpecFiles(t, tt.files)
+			var paths []string
+			for _, path := range tt.paths {
+				paths = append(paths, filepath.Join(dir, path))
+			}
+
+			doc, err := loadSpecs(paths)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if schemas := sortedKeys(doc.Components.Schemas); !reflect.DeepEqual(schemas, tt.schemas) {
+				t.Errorf("schemas = %v, want %v", schemas, tt.schemas)
+			}
+			if methods := sortedKeys(doc.Paths.Map()); !reflect.DeepEqual(methods, tt.methods) {
+				t.Errorf("paths = %v, want %v", methods, tt.methods)
+			}
+			for _, path := range doc.Paths.Map() {
+				ref := path.Post.RequestBody.Value.Content["application/json"].Schema.Ref
+				if !strings.HasPrefix(ref, "#/components/schemas/") {
+					t.Errorf("request body $ref = %s, want a local component ref", ref)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// structBuilder collects the struct types generated for a package, including named types
+// for inline nested objects, together with the regex patterns and validations they need.
+type structBuilder struct {
+	types        []TypeDef
+	enums        []EnumDef
+	unions       []UnionDef
+	memberships  map[string][]UnionMembership // union memberships of variant structs by type name
+	errorSchemas map[string]ErrorSchema       // error response schemas by type name
+	patterns     []Pattern
+	validations  []Validation
+	imports      map[string]bool // packages imported by rich field types (formats and x-go-type)
+	usesDate     bool            // a field uses the generated Date type
+
+	nestedValidation  bool // a Validate method validates nested structs or unions
+	indexedValidation bool // a Validate method validates the elements of a slice
+}
+
+// addStruct generates a struct type named name for an object schema.
+func (b *structBuilder) addStruct(name string, schema *openapi3.Schema) {
+	fields := []Field{}
+	var unionFields []UnionField
+	for _, propName := range sortedKeys(schema.Properties) {
+		propRef := schema.Properties[propName]
+		prop := propRef.Value
+		fieldName := capitalizeFirst(propName)
+		goType := b.goType(propRef, name+fieldName)
+		required := contains(schema.Required, propName)
+		b.addTypeImports(propRef)
+
+		// Union values are interfaces and need discriminator-aware decoding
+		unionField := unionFieldOf(propRef, fieldName, propName)
+		if unionField != nil {
+			unionFields = append(unionFields, *unionField)
+		}
+
+		// Optional and nullable values become pointers, so "not sent" and null differ from the zero value
+		pointer := unionField == nil && !isNilable(propRef, goType) && (prop.Nullable || !required && optionalMode(schema, prop) == optionalPointer)
+		fieldType := goType
+		jsonTag := propName
+		if !required {
+			jsonTag += ",omitempty"
+		}
+
+		// Translate schema constraints into checks for the generated Validate method
+		var validations []Validation
+		if pointer {
+			fieldType = "*" + goType
+			if isEnum(prop) {
+				validations = append(validations, enumValidation("r."+fieldName, prop, true))
+			}
+			constraints, pattern := buildValidations(prop, "*r."+fieldName, goType, lowerFirst(name)+fieldName+"Pattern")
+			validations = append(validations, constraints...)
+			if pattern != nil {
+				b.patterns = append(b.patterns, *pattern)
+			}
+			// Constraints only apply to values that were sent
+			for i := range validations {
+				validations[i].Condition = fmt.Sprintf("r.%s != nil && %s", fieldName, validations[i].Condition)
+			}
+		} else {
+			if required {
+				if v := requiredValidation("r."+fieldName, goType, unionField != nil); v != nil {
+					validations = append(validations, *v)
+				}
+			}
+			if isEnum(prop) {
+				validations = append(validations, enumValidation("r."+fieldName, prop, required))
+			}
+			constraints, pattern := buildValidations(prop, "r."+fieldName, goType, lowerFirst(name)+fieldName+"Pattern")
+			validations = append(validations, constraints...)
+			if pattern != nil {
+				b.patterns = append(b.patterns, *pattern)
+			}
+		}
+		b.validations = append(b.validations, validations...)
+
+		// Constraints on the items of arrays of strings and numbers are checked on each element
+		items, itemPattern := itemValidations(propRef, goType, lowerFirst(name)+fieldName+"ItemPattern")
+		if itemPattern != nil {
+			b.patterns = append(b.patterns, *itemPattern)
+		}
+		if len(items) > 0 {
+			b.indexedValidation = true
+		}
+		b.validations = append(b.validations, items...)
+
+		// Generated structs and unions validate themselves, and their errors are reported under this field
+		nested := nestedValidation(propRef, pointer, required)
+		if nested != nil && nested.Slice {
+			b.indexedValidation = true
+		}
+		b.nestedValidation = b.nestedValidation || nested != nil
+
+		fields = append(fields, Field{
+			Name:        fieldName,
+			Type:        fieldType,
+			JSONName:    propName,
+			JSONTag:     jsonTag,
+			Comment:     prop.Description,
+			Validations: validations,
+			Items:       items,
+			Nested:      nested,
+		})
+	}
+
+	typeDef := TypeDef{
+		Name:        name,
+		Description: schema.Description,
+		Fields:      fields,
+		UnionFields: unionFields,
+		Memberships: b.memberships[name],
+	}
+	if errorSchema, ok := b.errorSchemas[name]; ok {
+		typeDef.ErrorCode = errorSchema.Code
+		typeDef.ErrorCodeConst = errorSchema.CodeConst
+		for _, field := range fields {
+			if field.JSONName == "message" && field.Type == "string" {
+				typeDef.HasMessage = true
+			}
+		}
+	}
+	b.types = append(b.types, typeDef)
+}
+
+// goType returns the Go type for a property. Inline objects with properties become named
+// structs and inline enums named enum types called nameHint (array items get an "Item" suffix); everything else maps via getGoType.
+func (b *structBuilder) goType(schemaRef *openapi3.SchemaRef, nameHint string) string {
+	if schemaRef == nil || schemaRef.Ref != "" || schemaRef.Value == nil {
+		return getGoType(schemaRef)
+	}
+
+	schema := schemaRef.Value
+	switch {
+	case isInlineObject(schema):
+		b.addStruct(nameHint, schema)
+		return nameHint
+	case isEnum(schema):
+		b.addEnum(nameHint, schema)
+		return nameHint
+	case schema.Type.Is("array") && schema.Items != nil && schema.Items.Ref == "" && schema.Items.Value != nil:
+		return "[]" + b.goType(schema.Items, nameHint+"Item")
+	default:
+		return getGoType(schemaRef)
+	}
+}
+
+// addTypeImports records the packages and generated types the Go type of a property depends on.
+func (b *structBuilder) addTypeImports(propRef *openapi3.SchemaRef) {
+	imports, usesDate := goTypeImports(propRef)
+	if b.imports == nil {
+		b.imports = make(map[string]bool)
+	}
+	for _, importPath := range imports {
+		b.imports[importPath] = true
+	}
+	if usesDate {
+		b.imports["time"] = true
+		b.usesDate = true
+	}
+}
+
+// needsJSON reports whether the generated types require custom JSON encoding code.
+func (b *structBuilder) needsJSON() bool {
+	if len(b.enums) > 0 || len(b.unions) > 0 || b.usesDate {
+		return true
+	}
+	for _, t := range b.types {
+		if len(t.UnionFields) > 0 || len(t.Memberships) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Values of the x-go-optional extension, which selects how optional properties are generated.
+const (
+	optionalPointer = "pointer" // *T with omitempty: a missing value is nil (the default)
+	optionalValue   = "value"   // T with omitempty: a missing value is the zero value
+)
+
+// optionalMode returns how an optional property is generated. x-go-optional on the property
+// takes precedence over x-go-optional on its parent schema.
+func optionalMode(parent, prop *openapi3.Schema) string {
+	for _, schema := range []*openapi3.Schema{prop, parent} {
+		if mode, ok := schema.Extensions["x-go-optional"].(string); ok {
+			return mode
+		}
+	}
+	return optionalPointer
+}
+
+// checkOptionalModes fails on x-go-optional values other than "pointer" and "value" in the
+// component schemas, so a typo does not silently fall back to pointers.
+func checkOptionalModes(doc *openapi3.T) error {
+	if doc.Components == nil {
+		return nil
+	}
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		if err := checkOptionalMode(name, doc.Components.Schemas[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkOptionalMode checks the x-go-optional value of a schema and of its inline properties and items.
+func checkOptionalMode(path string, schemaRef *openapi3.SchemaRef) error {
+	if schemaRef == nil || schemaRef.Value == nil {
+		return nil
+	}
+	schema := schemaRef.Value
+	if value, ok := schema.Extensions["x-go-optional"]; ok {
+		if mode, _ := value.(string); mode != optionalPointer && mode != optionalValue {
+			return fmt.Errorf("x-go-optional of %s must be %q or %q, got %v", path, optionalPointer, optionalValue, value)
+		}
+	}
+	for _, name := range sortedKeys(schema.Properties) {
+		if prop := schema.Properties[name]; prop.Ref == "" {
+			if err := checkOptionalMode(path+"."+name, prop); err != nil {
+				return err
+			}
+		}
+	}
+	if schema.Items != nil && schema.Items.Ref == "" {
+		return checkOptionalMode(path+"[]", schema.Items)
+	}
+	return nil
+}
+
+// isNilable reports whether the Go type of a property can already represent a missing value.
+// Compositions without a discriminator are generated as interface{} aliases.
+func isNilable(propRef *openapi3.SchemaRef, goType string) bool {
+	if goType == "interface{}" || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || strings.HasPrefix(goType, "*") {
+		return true
+	}
+	prop := propRef.Value
+	return prop != nil && (len(prop.OneOf) > 0 || len(prop.AnyOf) > 0)
+}
+
+// isInlineObject reports whether an unreferenced schema should become its own named struct
+func isInlineObject(schema *openapi3.Schema) bool {
+	return schema.Type.Is("object") && len(schema.Properties) > 0
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestCheckOptionalModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas string
+		err     string // Substring of the expected error; "" when the schemas are valid
+	}{
+		{
+			name: "valid modes",
+			schemas: `
+    Order:
+      type: object
+      x-go-optional: value
+      properties:
+        note:
+          type: string
+          x-go-optional: pointer`,
+		},
+		{
+			name: "typo on schema",
+			schemas: `
+    Order:
+      type: object
+      x-go-optional: values
+      properties:
+        note:
+          type: string`,
+			err: `x-go-optional of Order must be "pointer" or "value", got values`,
+		},
+		{
+			name: "typo on inline property",
+			schemas: `
+    Order:
+      type: object
+      properties:
+        lines:
+          type: array
+          items:
+            type: object
+            properties:
+              sku:
+                type: string
+                x-go-optional: ptr`,
+			err: "x-go-optional of Order.lines[].sku",
+		},
+		{
+			name: "not a string",
+			schemas: `
+    Order:
+      type: object
+      properties:
+        note:
+          type: string
+          x-go-optional: true`,
+			err: "x-go-optional of Order.note",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := "openapi: 3.0.3\ninfo:\n  title: T\n  version: 1.0.0\npaths: {}\ncomponents:\n  schemas:" + tt.schemas + "\n"
+			doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+			if err != nil {
+				t.Fatalf("failed to load spec: %v", err)
+			}
+
+			err = checkOptionalModes(doc)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// embeddedTemplates are the default templates, compiled into the binary so the generator works
+// from any working directory.
+//
+//go:embed templates
+var embeddedTemplates embed.FS
+
+// templateDir is the --templates directory. A template found there by name replaces the
+// embedded template of the same name.
+var templateDir string
+
+// commentWidth is the column at which the comment function wraps doc comments.
+const commentWidth = 100
+
+// templateFuncs is the function library available to the default and custom templates.
+var templateFuncs = template.FuncMap{
+	"upperFirst": capitalizeFirst, // "accountId" -> "AccountId"
+	"lowerFirst": lowerFirst,      // "AccountId" -> "accountId"
+	"pascalCase": enumConstSuffix, // "monthly-statement" -> "MonthlyStatement"
+	"camelCase":  camelCase,       // "monthly-statement" -> "monthlyStatement"
+	"snakeCase":  toSnakeCase,     // "BankAccount" -> "bank_account"
+	"kebabCase":  kebabCase,       // "BankAccount" -> "bank-account"
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"comment":    comment, // {{comment .Description}} -> "// ..." lines wrapped at 100 columns
+	"wrap":       wrap,    // {{wrap 80 "\t// " .Description}}
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+
+	// TypeScript target
+	"tsType":       tsType,       // "[]AccountEvent" -> "AccountEvent[]"
+	"tsProperties": tsProperties, // interface properties of a TypeDef
+}
+
+// loadTemplate parses the template called name, from templateDir when it holds an override
+// and from the embedded defaults otherwise.
+func loadTemplate(name string) (*template.Template, error) {
+	var text []byte
+	var err error
+	if templateDir != "" {
+		text, err = os.ReadFile(filepath.Join(templateDir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if text == nil {
+		text, err = embeddedTemplates.ReadFile("templates/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown template %s: %v", name, err)
+		}
+	}
+	return template.New(name).Funcs(templateFuncs).Parse(string(text))
+}
+
+func camelCase(s string) string {
+	return lowerFirst(enumConstSuffix(s))
+}
+
+func kebabCase(s string) string {
+	return strings.ReplaceAll(toSnakeCase(s), "_", "-")
+}
+
+// comment formats text as a Go line comment wrapped at commentWidth columns.
+func comment(text string) string {
+	return wrap(commentWidth, "// ", text)
+}
+
+// wrap word-wraps text so that lines, including prefix, fit in width columns. Blank lines
+// separate paragraphs and are kept; words longer than a line are not split.
+func wrap(width int, prefix, text string) string {
+	var lines []string
+	for i, paragraph := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if i > 0 {
+			lines = append(lines, strings.TrimRight(prefix, " "))
+		}
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(prefix)+len(line)+1+len(word) > width {
+				lines = append(lines, prefix+line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, prefix+line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestLoadTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "typescript"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	overrides := map[string]string{
+		"types.tmpl":               "// custom types of {{.}}",
+		"typescript/index.ts.tmpl": "// custom index of {{.}}",
+	}
+	for name, text := range overrides {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		templateDir string
+		template    string
+		want        string // Output of the template executed with "Wallet"; "" for an embedded default
+		err         string // Substring of the expected error
+	}{
+		{name: "embedded default", template: "types.tmpl"},
+		{name: "override", templateDir: dir, template: "types.tmpl", want: "// custom types of Wallet"},
+		{name: "override in subdirectory", templateDir: dir, template: "typescript/index.ts.tmpl", want: "// custom index of Wallet"},
+		{name: "fallback to embedded default", templateDir: dir, template: "client.tmpl"},
+		{name: "unknown template", templateDir: dir, template: "stubs.tmpl", err: "unknown template stubs.tmpl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(dir string) { templateDir = dir }(templateDir)
+			templateDir = tt.templateDir
+
+			tmpl, err := loadTemplate(tt.template)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == "" {
+				return
+			}
+
+			var out strings.Builder
+			if err := tmpl.Execute(&out, "Wallet"); err != nil {
+				t.Fatalf("failed to execute template: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"upperFirst", `{{upperFirst "accountId"}}`, "AccountId"},
+		{"lowerFirst", `{{lowerFirst "AccountId"}}`, "accountId"},
+		{"pascalCase", `{{pascalCase "monthly-statement"}}`, "MonthlyStatement"},
+		{"camelCase", `{{camelCase "monthly-statement"}}`, "monthlyStatement"},
+		{"snakeCase", `{{snakeCase "BankAccount"}}`, "bank_account"},
+		{"kebabCase", `{{kebabCase "BankAccount"}}`, "bank-account"},
+		{"lower", `{{lower "Wallet"}}`, "wallet"},
+		{"upper", `{{upper "Wallet"}}`, "WALLET"},
+		{"trimPrefix", `{{trimPrefix "On" "OnReset"}}`, "Reset"},
+		{"comment", `{{comment "Deposits money."}}`, "// Deposits money."},
+		{"wrap", `{{wrap 13 "// " "one two three four"}}`, "// one two\n// three four"},
+		{"wrap paragraphs", `{{wrap 80 "\t// " "First.\n\nSecond."}}`, "\t// First.\n\t//\n\t// Second."},
+		{"wrap long word", `{{wrap 8 "// " "abcdefghij k"}}`, "// abcdefghij\n// k"},
+		{"tsType slice", `{{tsType "[]*AccountEvent"}}`, "AccountEvent[]"},
+		{"tsType map", `{{tsType "map[string]int64"}}`, "Record<string, number>"},
+		{"tsType time", `{{tsType "time.Time"}}`, "string"},
+		{"tsType custom Go type", `{{tsType "decimal.Decimal"}}`, "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New(tt.name).Funcs(templateFuncs).Parse(tt.template)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			var out strings.Builder
+			if err := tmpl.Execute(&out, nil); err != nil {
+				t.Fatalf("failed to execute template: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// TypeScriptTypesData is the data of the TypeScript types template: the same type model the
+// Go types template renders.
+type TypeScriptTypesData struct {
+	Types       []TypeDef
+	TypeAliases []TypeAlias
+	Enums       []EnumDef
+	Unions      []UnionDef
+}
+
+// TypeScriptActor is an actor rendered by the TypeScript client template.
+type TypeScriptActor struct {
+	FileName string // Module name without extension (e.g. "counterActor")
+	Actor    ActorInterface
+	Types    []string // Types the method signatures use, imported from ./types
+}
+
+// TSProperty is a rendered property of a TypeScript interface.
+type TSProperty struct {
+	Comment     string
+	Declaration string // e.g. "balance?: number"
+}
+
+// generateTypeScript writes the TypeScript client into outputDir: dapr.ts with the invocation
+// runtime, types.ts with the schemas used by the actors, one client module per actor and index.ts.
+func generateTypeScript(doc *openapi3.T, outputDir string, actorTypes []string, actorMethods map[string][]Method, actorSchemas map[string]map[string]bool, out *outputWriter) error {
+	schemaNames := make(map[string]bool)
+	for _, names := range actorSchemas {
+		for name := range names {
+			schemaNames[name] = true
+		}
+	}
+	builder, typeAliases, err := buildTypes(doc, schemaNames, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	typesData := TypeScriptTypesData{
+		Types:       builder.types,
+		TypeAliases: typeAliases,
+		Enums:       builder.enums,
+		Unions:      builder.unions,
+	}
+	if err := writeTypeScriptFile(filepath.Join(outputDir, "types.ts"), "types.ts.tmpl", typesData, out); err != nil {
+		return err
+	}
+	if err := writeTypeScriptFile(filepath.Join(outputDir, "dapr.ts"), "dapr.ts.tmpl", nil, out); err != nil {
+		return err
+	}
+
+	var actors []TypeScriptActor
+	for _, actorType := range actorTypes {
+		methods := actorMethods[actorType]
+		if len(methods) == 0 {
+			continue
+		}
+		actor := TypeScriptActor{
+			FileName: camelCase(actorType),
+			Actor: ActorInterface{
+				ActorType: actorType,
+				Methods:   methods,
+				Errors:    actorErrorTypes(methods),
+			},
+		}
+		seen := make(map[string]bool)
+		for _, method := range methods {
+			for _, typeName := range []string{method.RequestType, method.ReturnType} {
+				if typeName != "" && !seen[typeName] && tsType(typeName) == typeName {
+					seen[typeName] = true
+					actor.Types = append(actor.Types, typeName)
+				}
+			}
+		}
+
+		if err := writeTypeScriptFile(filepath.Join(outputDir, actor.FileName+".ts"), "client.ts.tmpl", actor, out); err != nil {
+			return err
+		}
+		actors = append(actors, actor)
+	}
+
+	return writeTypeScriptFile(filepath.Join(outputDir, "index.ts"), "index.ts.tmpl", actors, out)
+}
+
+// writeTypeScriptFile renders a template from templates/typescript into path.
+func writeTypeScriptFile(path, templateName string, data interface{}, out *outputWriter) error {
+	tmpl, err := loadTemplate("typescript/" + templateName)
+	if err != nil {
+		return fmt.Errorf("failed to parse TypeScript %s template: %v", templateName, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute %s template: %v", tmpl.Name(), err)
+	}
+	return out.writeFile(path, normalizeBlankLines(buf.Bytes()))
+}
+
+// tsType converts a Go type of the type model into the TypeScript type of its JSON encoding.
+func tsType(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		return tsType(goType[1:])
+	case strings.HasPrefix(goType, "[]"):
+		item := tsType(goType[2:])
+		if strings.Contains(item, " ") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case strings.HasPrefix(goType, "map[string]"):
+		return "Record<string, " + tsType(goType[len("map[string]"):]) + ">"
+	}
+
+	switch goType {
+	case "string", "time.Time", "uuid.UUID", dateTypeName:
+		return "string"
+	case "int", "int32", "int64", "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	case "interface{}":
+		return "unknown"
+	}
+	if strings.Contains(goType, ".") {
+		return "unknown" // x-go-type: the JSON encoding is defined by the Go type
+	}
+	return goType
+}
+
+// tsProperties renders the properties of a struct type. Optional properties are marked with
+// "?", nullable required ones accept null, and the discriminator of a union variant is narrowed
+// to the variant's value so TypeScript can discriminate the union.
+func tsProperties(t TypeDef) []TSProperty {
+	var properties []TSProperty
+	for _, field := range t.Fields {
+		name := field.JSONName
+		if !isIdentifier(name) {
+			name = strconv.Quote(name)
+		}
+
+		optional := strings.HasSuffix(field.JSONTag, ",omitempty")
+		propertyType := tsType(field.Type)
+		for _, membership := range t.Memberships {
+			if membership.PropertyField == field.Name {
+				propertyType = strconv.Quote(membership.Value)
+				optional = false // Variants always encode their discriminator
+			}
+		}
+
+		declaration := name + ": " + propertyType
+		if optional {
+			declaration = name + "?: " + propertyType
+		} else if strings.HasPrefix(field.Type, "*") {
+			declaration += " | null"
+		}
+		properties = append(properties, TSProperty{Comment: field.Comment, Declaration: declaration})
+	}
+	return properties
+}
+
+// normalizeBlankLines collapses runs of blank lines left by template actions into one.
+func normalizeBlankLines(source []byte) []byte {
+	lines := strings.Split(strings.TrimSpace(string(source)), "\n")
+	var out []string
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" && i > 0 && out[len(out)-1] == "" {
+			continue
+		}
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, "\n") + "\n")
+}
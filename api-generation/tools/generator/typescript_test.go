@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTSProperties(t *testing.T) {
+	tests := []struct {
+		name       string
+		schema     string
+		properties []string
+	}{
+		{
+			name:       "required and optional",
+			schema:     "type: object\nrequired: [amount]\nproperties:\n  amount:\n    type: number\n  note:\n    type: string",
+			properties: []string{"amount: number", "note?: string"},
+		},
+		{
+			name:       "nullable required",
+			schema:     "type: object\nrequired: [note]\nproperties:\n  note:\n    type: string\n    nullable: true",
+			properties: []string{"note: string | null"},
+		},
+		{
+			name:       "arrays and maps",
+			schema:     "type: object\nrequired: [tags, limits]\nproperties:\n  tags:\n    type: array\n    items:\n      type: string\n  limits:\n    type: object\n    additionalProperties:\n      type: integer",
+			properties: []string{"limits: Record<string, number>", "tags: string[]"},
+		},
+		{
+			name:       "rich formats",
+			schema:     "type: object\nrequired: [at, id]\nproperties:\n  at:\n    type: string\n    format: date-time\n  id:\n    type: string\n    format: uuid",
+			properties: []string{"at: string", "id: string"},
+		},
+		{
+			name:       "quoted names",
+			schema:     "type: object\nrequired: [x-trace-id]\nproperties:\n  x-trace-id:\n    type: string",
+			properties: []string{`"x-trace-id": string`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &structBuilder{}
+			b.addStruct("Order", loadTestSchema(t, tt.schema))
+
+			var properties []string
+			for _, property := range tsProperties(b.types[len(b.types)-1]) {
+				properties = append(properties, property.Declaration)
+			}
+			if !reflect.DeepEqual(properties, tt.properties) {
+				t.Errorf("properties = %q, want %q", properties, tt.properties)
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{"shop.yaml": registrySpec})
+	config := &Config{Specs: []string{"shop.yaml"}, Output: "internal", Module: "example.com/shop", TypeScript: "ts", dir: dir}
+	doc, err := loadSpecs(config.specPaths())
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+	if err := generateActorPackages(doc, config, &outputWriter{}); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want []string // Substrings of the file
+	}{
+		{
+			file: "index.ts",
+			want: []string{`export * from "./dapr";`, `export * from "./types";`, `export * from "./shopActor";`, `export * from "./walletActor";`},
+		},
+		{
+			file: "types.ts",
+			want: []string{"export interface Items {\n", "names?: string[];", "export interface Balance {\n", "amount?: number;"},
+		},
+		{
+			file: "shopActor.ts",
+			want: []string{
+				`import type { Items } from "./types";`,
+				`export const ActorTypeShopActor = "ShopActor";`,
+				"addItem(request: Items): Promise<unknown> {",
+				`invokeActor<Items>(this.options, ActorTypeShopActor, this.actorId, "GetItems");`,
+			},
+		},
+		{
+			file: "walletActor.ts",
+			want: []string{`import type { Balance } from "./types";`, "getBalance(): Promise<Balance> {"},
+		},
+		{
+			file: "dapr.ts",
+			want: []string{"export async function invokeActor<"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			source, err := os.ReadFile(filepath.Join(dir, "ts", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(source), want) {
+					t.Errorf("%s does not contain %q:\n%s", tt.file, want, source)
+				}
+			}
+		})
+	}
+}
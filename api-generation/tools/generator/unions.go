@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// UnionDef is a sealed interface generated from a oneOf schema with a discriminator.
+type UnionDef struct {
+	Name          string
+	Description   string
+	PropertyName  string // JSON name of the discriminator property
+	PropertyField string // Go name of the discriminator property
+	Variants      []UnionVariant
+}
+
+// UnionVariant maps a discriminator value to the struct type implementing the union.
+type UnionVariant struct {
+	TypeName string
+	Value    string
+}
+
+// UnionMembership records that a struct type is a variant of a union.
+type UnionMembership struct {
+	UnionName     string
+	PropertyField string
+	Value         string
+}
+
+// UnionField is a struct field holding a union value that needs discriminator-aware decoding.
+type UnionField struct {
+	Name      string
+	JSONName  string
+	UnionName string
+	IsSlice   bool
+}
+
+// isUnion reports whether schema is a oneOf the generator can decode by discriminator.
+func isUnion(schema *openapi3.Schema) bool {
+	return schema != nil && len(schema.OneOf) > 0 && schema.Discriminator != nil && schema.Discriminator.PropertyName != ""
+}
+
+// buildUnion describes the union generated for a oneOf + discriminator schema called name.
+// Every variant must be a $ref to an object schema declaring the discriminator property.
+func buildUnion(name string, schema *openapi3.Schema) (UnionDef, error) {
+	union := UnionDef{
+		Name:          name,
+		Description:   schema.Description,
+		PropertyName:  schema.Discriminator.PropertyName,
+		PropertyField: capitalizeFirst(schema.Discriminator.PropertyName),
+	}
+
+	// Explicit mapping first, then implicit mapping by schema name
+	valueByType := make(map[string]string)
+	for _, value := range sortedKeys(schema.Discriminator.Mapping) {
+		typeName := refName(schema.Discriminator.Mapping[value])
+		if _, ok := valueByType[typeName]; !ok {
+			valueByType[typeName] = value
+		}
+	}
+
+	for _, variantRef := range schema.OneOf {
+		if variantRef.Ref == "" {
+			return union, fmt.Errorf("oneOf variant of %s must be a $ref to a component schema", name)
+		}
+		typeName := refName(variantRef.Ref)
+		if variantRef.Value == nil || variantRef.Value.Properties[union.PropertyName] == nil {
+			return union, fmt.Errorf("oneOf variant %s of %s must declare discriminator property %q", typeName, name, union.PropertyName)
+		}
+
+		value, ok := valueByType[typeName]
+		if !ok {
+			value = typeName
+		}
+		union.Variants = append(union.Variants, UnionVariant{TypeName: typeName, Value: value})
+	}
+
+	return union, nil
+}
+
+// collectUnionMemberships maps each variant struct name to the unions it belongs to.
+func collectUnionMemberships(doc *openapi3.T) (map[string][]UnionMembership, error) {
+	memberships := make(map[string][]UnionMembership)
+	if doc.Components == nil {
+		return memberships, nil
+	}
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		schemaRef := doc.Components.Schemas[name]
+		if !isUnion(schemaRef.Value) {
+			continue
+		}
+		union, err := buildUnion(name, schemaRef.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, variant := range union.Variants {
+			memberships[variant.TypeName] = append(memberships[variant.TypeName], UnionMembership{
+				UnionName:     name,
+				PropertyField: union.PropertyField,
+				Value:         variant.Value,
+			})
+		}
+	}
+
+	for _, list := range memberships {
+		sort.Slice(list, func(i, j int) bool { return list[i].UnionName < list[j].UnionName })
+	}
+
+	return memberships, nil
+}
+
+// promoteSharedUnions moves unions into the shared set when any of their variants is shared.
+// The sealed marker method is unexported, so a union and its variants must live in one package.
+func promoteSharedUnions(doc *openapi3.T, shared map[string]bool) {
+	if doc.Components == nil {
+		return
+	}
+	for name, schemaRef := range doc.Components.Schemas {
+		if !isUnion(schemaRef.Value) || shared[name] {
+			continue
+		}
+		for _, variantRef := range schemaRef.Value.OneOf {
+			if shared[refName(variantRef.Ref)] {
+				shared[name] = true
+				break
+			}
+		}
+	}
+}
+
+// unionFieldOf returns the union decoding info for a property holding a union (or a slice of them).
+func unionFieldOf(propRef *openapi3.SchemaRef, fieldName, jsonName string) *UnionField {
+	if propRef == nil || propRef.Value == nil {
+		return nil
+	}
+	if propRef.Ref != "" && isUnion(propRef.Value) {
+		return &UnionField{Name: fieldName, JSONName: jsonName, UnionName: refName(propRef.Ref)}
+	}
+	items := propRef.Value.Items
+	if propRef.Value.Type.Is("array") && items != nil && items.Ref != "" && isUnion(items.Value) {
+		return &UnionField{Name: fieldName, JSONName: jsonName, UnionName: refName(items.Ref), IsSlice: true}
+	}
+	return nil
+}
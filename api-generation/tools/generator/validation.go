@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Validation is a single schema constraint rendered as Go code.
+type Validation struct {
+	Condition string // Go expression that is true when the value violates the constraint
+	Message   string // Human-readable description of the constraint
+}
+
+// NestedValidation describes a field whose value, or whose elements, are generated structs or
+// unions, so the generated Validate method also validates them.
+type NestedValidation struct {
+	Slice   bool // The field is a slice and each element is validated
+	Nilable bool // The value (or element) is a pointer or union interface that may be nil
+}
+
+// Pattern is a package-level compiled regular expression used by generated validation code.
+type Pattern struct {
+	Name string
+	Expr string
+}
+
+// buildValidations converts OpenAPI constraints on schema into Go validation checks.
+// expr is the Go expression holding the value (e.g. "r.Amount"), goType its Go type and
+// patternName the variable name to use if the schema declares a pattern.
+// Constraints that the Go type already guarantees (e.g. int32 bounds) are skipped, as are
+// constraints on rich types (formats and x-go-type), which validate themselves when decoded.
+func buildValidations(schema *openapi3.Schema, expr, goType, patternName string) ([]Validation, *Pattern) {
+	var validations []Validation
+	var pattern *Pattern
+
+	if _, ok := formatGoType(schema); ok {
+		return nil, nil
+	}
+
+	switch {
+	case schema.Type.Is("integer") || schema.Type.Is("number"):
+		isInteger := schema.Type.Is("integer")
+		if schema.Min != nil && !isImpliedByType(goType, *schema.Min, true) {
+			op, desc := "<", "greater than or equal to"
+			if schema.ExclusiveMin {
+				op, desc = "<=", "greater than"
+			}
+			limit := formatLimit(*schema.Min, isInteger, true)
+			validations = append(validations, Validation{
+				Condition: fmt.Sprintf("%s %s %s", expr, op, limit),
+				Message:   fmt.Sprintf("must be %s %s", desc, limit),
+			})
+		}
+		if schema.MultipleOf != nil {
+			validations = append(validations, multipleOfValidation(expr, goType, *schema.MultipleOf))
+		}
+		if schema.Max != nil && !isImpliedByType(goType, *schema.Max, false) {
+			op, desc := ">", "less than or equal to"
+			if schema.ExclusiveMax {
+				op, desc = ">=", "less than"
+			}
+			limit := formatLimit(*schema.Max, isInteger, false)
+			validations = append(validations, Validation{
+				Condition: fmt.Sprintf("%s %s %s", expr, op, limit),
+				Message:   fmt.Sprintf("must be %s %s", desc, limit),
+			})
+		}
+
+	case schema.Type.Is("string"):
+		if schema.MinLength == 1 {
+			validations = append(validations, Validation{
+				Condition: fmt.Sprintf("%s == \"\"", expr),
+				Message:   "must not be empty",
+			})
+		} else if schema.MinLength > 1 {
+			validations = append(validations, Validation{
+				Condition: fmt.Sprintf("utf8.RuneCountInString(%s) < %d", expr, schema.MinLength),
+				Message:   fmt.Sprintf("must be at least %s", plural(schema.MinLength, "character")),
+			})
+		}
+		if schema.MaxLength != nil {
+			validations = append(validations, Validation{
+				Condition: fmt.Sprintf("utf8.RuneCountInString(%s) > %d", expr, *schema.MaxLength),
+				Message:   fmt.Sprintf("must be at most %s", plural(*schema.MaxLength, "character")),
+			})
+		}
+		if schema.Pattern != "" {
+			pattern = &Pattern{Name: patternName, Expr: schema.Pattern}
+			validations = append(validations, Validation{
+				Condition: fmt.Sprintf("!%s.MatchString(%s)", patternName, expr),
+				Message:   fmt.Sprintf("must match pattern %s", schema.Pattern),
+			})
+		}
+
+	case schema.Type.Is("array"):
+		if schema.MinItems > 0 {
+			validations = append(validations, Validation{
+				Condition: fmt.Sprintf("len(%s) < %d", expr, schema.MinItems),
+				Message:   fmt.Sprintf("must contain at least %s", plural(schema.MinItems, "item")),
+			})
+		}
+		if schema.MaxItems != nil {
+			validations = append(validations, Validation{
+				Condition: fmt.Sprintf("len(%s) > %d", expr, *schema.MaxItems),
+				Message:   fmt.Sprintf("must contain at most %s", plural(*schema.MaxItems, "item")),
+			})
+		}
+	}
+
+	return validations, pattern
+}
+
+// multipleOfValidation returns the multipleOf check of a number. Integer types with an integer
+// factor are checked exactly; other values allow for floating-point rounding.
+func multipleOfValidation(expr, goType string, factor float64) Validation {
+	limit := strconv.FormatFloat(factor, 'g', -1, 64)
+	value := expr
+	if goType != "float64" {
+		value = fmt.Sprintf("float64(%s)", expr)
+	}
+	condition := fmt.Sprintf("math.Abs(%[1]s/%[2]s-math.Round(%[1]s/%[2]s)) > 1e-9", value, limit)
+	if isIntegerGoType(goType) && factor == math.Trunc(factor) {
+		condition = fmt.Sprintf("%s%%%s != 0", expr, limit)
+	}
+	return Validation{
+		Condition: condition,
+		Message:   fmt.Sprintf("must be a multiple of %s", limit),
+	}
+}
+
+// isIntegerGoType reports whether goType is a Go integer type.
+func isIntegerGoType(goType string) bool {
+	switch goType {
+	case "int", "int32", "int64":
+		return true
+	}
+	return false
+}
+
+// plural returns n followed by word, in the plural unless n is 1.
+func plural(n uint64, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// itemValidations returns the checks on each element of an array property whose items are inline
+// strings or numbers, rendered on the loop variable "item". Items that are generated types validate
+// themselves (see nestedValidation).
+func itemValidations(propRef *openapi3.SchemaRef, goType, patternName string) ([]Validation, *Pattern) {
+	prop := propRef.Value
+	if prop == nil || !prop.Type.Is("array") || prop.Items == nil || prop.Items.Ref != "" || prop.Items.Value == nil {
+		return nil, nil
+	}
+	items := prop.Items.Value
+	if !items.Type.Is("string") && !items.Type.Is("integer") && !items.Type.Is("number") {
+		return nil, nil
+	}
+	return buildValidations(items, "item", strings.TrimPrefix(goType, "[]"), patternName)
+}
+
+// nestedValidation returns the nested validation of a property, or nil when its Go type has no
+// Validate method. pointer is set when the field is generated as a pointer and required when a
+// presence check already rejects a missing union.
+func nestedValidation(propRef *openapi3.SchemaRef, pointer, required bool) *NestedValidation {
+	if propRef == nil || propRef.Value == nil {
+		return nil
+	}
+	if hasValidateMethod(propRef) {
+		return &NestedValidation{Nilable: pointer || isUnion(propRef.Value) && !required}
+	}
+	items := propRef.Value.Items
+	if propRef.Value.Type.Is("array") && items != nil && hasValidateMethod(items) {
+		return &NestedValidation{Slice: true, Nilable: isUnion(items.Value)}
+	}
+	return nil
+}
+
+// hasValidateMethod reports whether the Go type of a schema is a generated struct or union.
+func hasValidateMethod(schemaRef *openapi3.SchemaRef) bool {
+	schema := schemaRef.Value
+	if schema == nil || isExternalType(schema) {
+		return false
+	}
+	if isUnion(schema) {
+		return schemaRef.Ref != ""
+	}
+	if schemaRef.Ref != "" {
+		return schema.Type.Is("object") && schema.Properties != nil
+	}
+	return isInlineObject(schema)
+}
+
+// requiredValidation returns a presence check for required fields whose Go type can
+// represent "not sent" (nilable is set for interface types such as unions).
+// Scalar fields cannot be distinguished from their zero value.
+func requiredValidation(expr, goType string, nilable bool) *Validation {
+	if nilable || goType == "interface{}" || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || strings.HasPrefix(goType, "*") {
+		return &Validation{
+			Condition: fmt.Sprintf("%s == nil", expr),
+			Message:   "is required",
+		}
+	}
+	return nil
+}
+
+// isImpliedByType reports whether a minimum/maximum bound is already enforced by the Go type.
+func isImpliedByType(goType string, limit float64, isMin bool) bool {
+	var lo, hi float64
+	switch goType {
+	case "int32":
+		lo, hi = math.MinInt32, math.MaxInt32
+	case "int", "int64":
+		lo, hi = math.MinInt64, math.MaxInt64
+	default:
+		return false
+	}
+	if isMin {
+		return limit <= lo
+	}
+	return limit >= hi
+}
+
+// formatLimit renders a numeric bound as a Go literal for the field's type.
+func formatLimit(limit float64, isInteger, isMin bool) string {
+	if isInteger {
+		if isMin {
+			limit = math.Ceil(limit)
+		} else {
+			limit = math.Floor(limit)
+		}
+		return strconv.FormatInt(int64(limit), 10)
+	}
+	return strconv.FormatFloat(limit, 'g', -1, 64)
+}
+
+// validationImports returns the standard library packages needed by the generated checks.
+func validationImports(validations []Validation) []string {
+	var imports []string
+	for _, v := range validations {
+		if strings.Contains(v.Condition, "utf8.") && !contains(imports, "unicode/utf8") {
+			imports = append(imports, "unicode/utf8")
+		}
+		if strings.Contains(v.Condition, ".MatchString(") && !contains(imports, "regexp") {
+			imports = append(imports, "regexp")
+		}
+		if strings.Contains(v.Condition, "math.") && !contains(imports, "math") {
+			imports = append(imports, "math")
+		}
+	}
+	return imports
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// loadTestSchema loads an OpenAPI schema from YAML.
+func loadTestSchema(t *testing.T, schema string) *openapi3.Schema {
+	t.Helper()
+	spec := "openapi: 3.0.3\ninfo:\n  title: T\n  version: 1.0.0\npaths: {}\ncomponents:\n  schemas:\n    S:\n      " +
+		strings.ReplaceAll(schema, "\n", "\n      ") + "\n"
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+	return doc.Components.Schemas["S"].Value
+}
+
+func TestBuildValidations(t *testing.T) {
+	tests := []struct {
+		name        string
+		schema      string
+		goType      string
+		validations []Validation
+	}{
+		{
+			name:   "integer multipleOf",
+			schema: "type: integer\nmultipleOf: 5",
+			goType: "int32",
+			validations: []Validation{
+				{Condition: "r.N%5 != 0", Message: "must be a multiple of 5"},
+			},
+		},
+		{
+			name:   "number multipleOf",
+			schema: "type: number\nmultipleOf: 0.01",
+			goType: "float64",
+			validations: []Validation{
+				{Condition: "math.Abs(r.N/0.01-math.Round(r.N/0.01)) > 1e-9", Message: "must be a multiple of 0.01"},
+			},
+		},
+		{
+			name:   "integer with fractional multipleOf",
+			schema: "type: integer\nmultipleOf: 0.5",
+			goType: "int64",
+			validations: []Validation{
+				{Condition: "math.Abs(float64(r.N)/0.5-math.Round(float64(r.N)/0.5)) > 1e-9", Message: "must be a multiple of 0.5"},
+			},
+		},
+		{
+			name:   "single item",
+			schema: "type: array\nminItems: 1\nmaxItems: 1\nitems:\n  type: string",
+			goType: "[]string",
+			validations: []Validation{
+				{Condition: "len(r.N) < 1", Message: "must contain at least 1 item"},
+				{Condition: "len(r.N) > 1", Message: "must contain at most 1 item"},
+			},
+		},
+		{
+			name:   "several items",
+			schema: "type: array\nminItems: 2\nmaxItems: 5\nitems:\n  type: string",
+			goType: "[]string",
+			validations: []Validation{
+				{Condition: "len(r.N) < 2", Message: "must contain at least 2 items"},
+				{Condition: "len(r.N) > 5", Message: "must contain at most 5 items"},
+			},
+		},
+		{
+			name:   "single character",
+			schema: "type: string\nmaxLength: 1",
+			goType: "string",
+			validations: []Validation{
+				{Condition: "utf8.RuneCountInString(r.N) > 1", Message: "must be at most 1 character"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validations, _ := buildValidations(loadTestSchema(t, tt.schema), "r.N", tt.goType, "nPattern")
+			if !reflect.DeepEqual(validations, tt.validations) {
+				t.Errorf("validations = %+v, want %+v", validations, tt.validations)
+			}
+		})
+	}
+}
+
+func TestItemValidations(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		items   []Validation
+		pattern string
+	}{
+		{
+			name:   "string items",
+			schema: "type: array\nitems:\n  type: string\n  minLength: 2\n  maxLength: 10\n  pattern: '^[a-z]+$'",
+			items: []Validation{
+				{Condition: "utf8.RuneCountInString(item) < 2", Message: "must be at least 2 characters"},
+				{Condition: "utf8.RuneCountInString(item) > 10", Message: "must be at most 10 characters"},
+				{Condition: "!orderTagsItemPattern.MatchString(item)", Message: "must match pattern ^[a-z]+$"},
+			},
+			pattern: "^[a-z]+$",
+		},
+		{
+			name:   "number items",
+			schema: "type: array\nitems:\n  type: number\n  minimum: 0\n  maximum: 100",
+			items: []Validation{
+				{Condition: "item < 0", Message: "must be greater than or equal to 0"},
+				{Condition: "item > 100", Message: "must be less than or equal to 100"},
+			},
+		},
+		{
+			name:   "integer items",
+			schema: "type: array\nitems:\n  type: integer\n  format: int32\n  multipleOf: 5",
+			items: []Validation{
+				{Condition: "item%5 != 0", Message: "must be a multiple of 5"},
+			},
+		},
+		{
+			name:   "unconstrained items",
+			schema: "type: array\nitems:\n  type: string",
+		},
+		{
+			name:   "object items validate themselves",
+			schema: "type: array\nitems:\n  type: object\n  properties:\n    sku:\n      type: string\n      minLength: 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &openapi3.Schema{
+				Type:       &openapi3.Types{"object"},
+				Properties: openapi3.Schemas{"tags": openapi3.NewSchemaRef("", loadTestSchema(t, tt.schema))},
+			}
+			b := &structBuilder{}
+			b.addStruct("Order", order)
+
+			field := b.types[len(b.types)-1].Fields[0]
+			if !reflect.DeepEqual(field.Items, tt.items) {
+				t.Errorf("items = %+v, want %+v", field.Items, tt.items)
+			}
+			if b.indexedValidation != (len(tt.items) > 0 || field.Nested != nil) {
+				t.Errorf("indexedValidation = %v", b.indexedValidation)
+			}
+			if tt.pattern != "" && (len(b.patterns) != 1 || b.patterns[0].Expr != tt.pattern) {
+				t.Errorf("patterns = %+v, want %s", b.patterns, tt.pattern)
+			}
+		})
+	}
+}
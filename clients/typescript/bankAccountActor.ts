@@ -0,0 +1,49 @@
+// Typed client for BankAccountActor.
+//
+// Code generated from OpenAPI specification. DO NOT EDIT manually.
+
+import { invokeActor } from "./dapr";
+import type { ActorClientOptions } from "./dapr";
+import type { CreateAccountRequest, BankAccountState, DepositRequest, TransactionHistory, WithdrawRequest } from "./types";
+
+/** Dapr actor type identifier for BankAccountActor. */
+export const ActorTypeBankAccountActor = "BankAccountActor";
+
+/**
+ * Invokes the methods of one BankAccountActor instance through the Dapr HTTP API.
+ * Errors declared in the OpenAPI schema (AccountAlreadyExistsError, VersionConflictError, AccountNotFoundError, IdempotencyKeyConflictError, InsufficientFundsError) are thrown as ActorError.
+ */
+export class BankAccountActorClient {
+  readonly actorId: string;
+  private readonly options: ActorClientOptions;
+
+  constructor(actorId: string, options: ActorClientOptions = {}) {
+    this.actorId = actorId;
+    this.options = options;
+  }
+
+  /** Create new bank account */
+  createAccount(request: CreateAccountRequest): Promise<BankAccountState> {
+    return invokeActor<BankAccountState>(this.options, ActorTypeBankAccountActor, this.actorId, "CreateAccount", request);
+  }
+
+  /** Deposit money to account */
+  deposit(request: DepositRequest): Promise<BankAccountState> {
+    return invokeActor<BankAccountState>(this.options, ActorTypeBankAccountActor, this.actorId, "Deposit", request);
+  }
+
+  /** Get current account balance */
+  getBalance(): Promise<BankAccountState> {
+    return invokeActor<BankAccountState>(this.options, ActorTypeBankAccountActor, this.actorId, "GetBalance");
+  }
+
+  /** Get transaction history */
+  getHistory(): Promise<TransactionHistory> {
+    return invokeActor<TransactionHistory>(this.options, ActorTypeBankAccountActor, this.actorId, "GetHistory");
+  }
+
+  /** Withdraw money from account */
+  withdraw(request: WithdrawRequest): Promise<BankAccountState> {
+    return invokeActor<BankAccountState>(this.options, ActorTypeBankAccountActor, this.actorId, "Withdraw", request);
+  }
+}
@@ -0,0 +1,43 @@
+// Typed client for CounterActor.
+//
+// Code generated from OpenAPI specification. DO NOT EDIT manually.
+
+import { invokeActor } from "./dapr";
+import type { ActorClientOptions } from "./dapr";
+import type { CounterState, SetValueRequest } from "./types";
+
+/** Dapr actor type identifier for CounterActor. */
+export const ActorTypeCounterActor = "CounterActor";
+
+/**
+ * Invokes the methods of one CounterActor instance through the Dapr HTTP API.
+ */
+export class CounterActorClient {
+  readonly actorId: string;
+  private readonly options: ActorClientOptions;
+
+  constructor(actorId: string, options: ActorClientOptions = {}) {
+    this.actorId = actorId;
+    this.options = options;
+  }
+
+  /** Decrement counter by 1 */
+  decrement(): Promise<CounterState> {
+    return invokeActor<CounterState>(this.options, ActorTypeCounterActor, this.actorId, "Decrement");
+  }
+
+  /** Get current counter value */
+  get(): Promise<CounterState> {
+    return invokeActor<CounterState>(this.options, ActorTypeCounterActor, this.actorId, "Get");
+  }
+
+  /** Increment counter by 1 */
+  increment(): Promise<CounterState> {
+    return invokeActor<CounterState>(this.options, ActorTypeCounterActor, this.actorId, "Increment");
+  }
+
+  /** Set counter to specific value */
+  set(request: SetValueRequest): Promise<CounterState> {
+    return invokeActor<CounterState>(this.options, ActorTypeCounterActor, this.actorId, "Set", request);
+  }
+}
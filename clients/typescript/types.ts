@@ -0,0 +1,198 @@
+// Types of the actor API, generated from the OpenAPI component schemas.
+//
+// Code generated from OpenAPI specification. DO NOT EDIT manually.
+
+/** AccountEventType Type of event recorded for a bank account */
+export const AccountEventType = {
+  AccountCreated: "AccountCreated",
+  MoneyDeposited: "MoneyDeposited",
+  MoneyWithdrawn: "MoneyWithdrawn",
+} as const;
+export type AccountEventType = (typeof AccountEventType)[keyof typeof AccountEventType];
+
+/**
+ * AccountEventData Event-specific data, selected by the eventType discriminator
+ * Variants are selected by the "eventType" property.
+ */
+export type AccountEventData = AccountCreatedEventData | MoneyDepositedEventData | MoneyWithdrawnEventData;
+
+/** AccountAlreadyExistsError An account has already been created for this actor ID */
+export interface AccountAlreadyExistsError {
+  /** Human-readable error message */
+  message: string;
+}
+
+/** Stable code identifying AccountAlreadyExistsError in an ActorError. */
+export const ErrorCodeAccountAlreadyExists = "account_already_exists";
+
+/** AccountCreatedEventData Payload of an AccountCreated event */
+export interface AccountCreatedEventData {
+  /** Account creation timestamp */
+  createdAt: string;
+  /** Type of event recorded for a bank account */
+  eventType: "AccountCreated";
+  /** Initial deposit amount */
+  initialDeposit: number;
+  /** Name of the account owner */
+  ownerName: string;
+}
+
+/** AccountEvent A single account event */
+export interface AccountEvent {
+  /** Event-specific data, selected by the eventType discriminator */
+  data: AccountEventData;
+  /** Unique event identifier */
+  eventId: string;
+  /** Type of event recorded for a bank account */
+  eventType: AccountEventType;
+  /** Idempotency key of the command that stored the event */
+  idempotencyKey?: string;
+  /** When the event occurred */
+  timestamp: string;
+}
+
+/** AccountNotFoundError No account has been created for this actor ID */
+export interface AccountNotFoundError {
+  /** Human-readable error message */
+  message: string;
+}
+
+/** Stable code identifying AccountNotFoundError in an ActorError. */
+export const ErrorCodeAccountNotFound = "account_not_found";
+
+/** BankAccountState Current state of bank account (computed from events) */
+export interface BankAccountState {
+  /** Unique account identifier */
+  accountId: string;
+  /** Current account balance (computed from events) */
+  balance: number;
+  /** Account creation timestamp */
+  createdAt?: string;
+  /** Whether account is active */
+  isActive: boolean;
+  /** Account owner name */
+  ownerName: string;
+  /** Sequence number of the last event applied to the account; pass it as expectedVersion to make a command conditional */
+  version: number;
+}
+
+/** CounterState Current state of the counter actor (state-based) */
+export interface CounterState {
+  /** The current counter value */
+  value: number;
+}
+
+/** CreateAccountRequest Request to create a new bank account */
+export interface CreateAccountRequest {
+  /** Create the account only if the account is at this version (0 before it is created) */
+  expectedVersion?: number;
+  /** Initial deposit amount */
+  initialDeposit: number;
+  /** Name of the account owner */
+  ownerName: string;
+}
+
+/** DepositRequest Request to deposit money */
+export interface DepositRequest {
+  /** Amount to deposit */
+  amount: number;
+  /** Description of the deposit */
+  description: string;
+  /** Deposit only if the account is at this version */
+  expectedVersion?: number;
+  /** Key identifying this deposit; a retry with the same key returns the original result without depositing again, and reusing it for a different request fails */
+  idempotencyKey?: string;
+}
+
+/** IdempotencyKeyConflictError The idempotency key was already used by a request of another method or with other values */
+export interface IdempotencyKeyConflictError {
+  /** The reused idempotency key */
+  idempotencyKey: string;
+  /** Human-readable error message */
+  message: string;
+}
+
+/** Stable code identifying IdempotencyKeyConflictError in an ActorError. */
+export const ErrorCodeIdempotencyKeyConflict = "idempotency_key_conflict";
+
+/** InsufficientFundsError The account balance does not cover the requested withdrawal */
+export interface InsufficientFundsError {
+  /** Current account balance */
+  balance: number;
+  /** Human-readable error message */
+  message: string;
+  /** Amount requested for withdrawal */
+  requested: number;
+}
+
+/** Stable code identifying InsufficientFundsError in an ActorError. */
+export const ErrorCodeInsufficientFunds = "insufficient_funds";
+
+/** MoneyDepositedEventData Payload of a MoneyDeposited event */
+export interface MoneyDepositedEventData {
+  /** Amount deposited */
+  amount: number;
+  /** Description of the deposit */
+  description: string;
+  /** Type of event recorded for a bank account */
+  eventType: "MoneyDeposited";
+  /** When the deposit was made */
+  timestamp: string;
+}
+
+/** MoneyWithdrawnEventData Payload of a MoneyWithdrawn event */
+export interface MoneyWithdrawnEventData {
+  /** Amount withdrawn */
+  amount: number;
+  /** Description of the withdrawal */
+  description: string;
+  /** Type of event recorded for a bank account */
+  eventType: "MoneyWithdrawn";
+  /** When the withdrawal was made */
+  timestamp: string;
+}
+
+/** ResetReminderPayload Payload of the counter reset reminder */
+export interface ResetReminderPayload {
+  /** The value to reset the counter to */
+  value: number;
+}
+
+/** SetValueRequest Request to set the counter to a specific value */
+export interface SetValueRequest {
+  /** The value to set the counter to */
+  value: number;
+}
+
+/** TransactionHistory Complete transaction history (event sourcing benefit) */
+export interface TransactionHistory {
+  /** Account identifier */
+  accountId: string;
+  /** List of all events in chronological order */
+  events: AccountEvent[];
+}
+
+/** VersionConflictError The account is not at the version the command expected */
+export interface VersionConflictError {
+  /** Current version of the account */
+  currentVersion: number;
+  /** Version the command expected */
+  expectedVersion: number;
+  /** Human-readable error message */
+  message: string;
+}
+
+/** Stable code identifying VersionConflictError in an ActorError. */
+export const ErrorCodeVersionConflict = "version_conflict";
+
+/** WithdrawRequest Request to withdraw money */
+export interface WithdrawRequest {
+  /** Amount to withdraw */
+  amount: number;
+  /** Description of the withdrawal */
+  description: string;
+  /** Withdraw only if the account is at this version */
+  expectedVersion?: number;
+  /** Key identifying this withdrawal; a retry with the same key returns the original result without withdrawing again, and reusing it for a different request fails */
+  idempotencyKey?: string;
+}
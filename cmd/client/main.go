@@ -2,12 +2,11 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"time"
 
 	"github.com/dapr/go-sdk/client"
-	
+
 	"github.com/shogotsuneto/dapr-actor-experiment/internal/counteractor"
 )
 
@@ -20,44 +19,29 @@ func main() {
 	defer c.Close()
 
 	ctx := context.Background()
-	actorType := "CounterActor"
 	actorID := "counter-1"
 
+	// Generated typed client - method names and payloads come from the OpenAPI schema
+	counter := counteractor.NewCounterActorClient(c, actorID)
+
 	log.Println("=== Dapr Actor Demo Client ===")
-	log.Printf("Interacting with actor: %s/%s\n", actorType, actorID)
+	log.Printf("Interacting with actor: %s/%s\n", counteractor.ActorTypeCounterActor, actorID)
 
 	// Test 1: Get initial value
 	log.Println("\n1. Getting initial counter value...")
-	response, err := c.InvokeActor(ctx, &client.InvokeActorRequest{
-		ActorType: actorType,
-		ActorID:   actorID,
-		Method:    "get",
-	})
+	state, err := counter.Get(ctx)
 	if err != nil {
 		log.Fatalf("Failed to get counter value: %v", err)
 	}
-
-	var state counteractor.CounterState
-	if err := json.Unmarshal(response.Data, &state); err != nil {
-		log.Fatalf("Failed to unmarshal response: %v", err)
-	}
 	log.Printf("Initial value: %d", state.Value)
 
 	// Test 2: Increment counter 5 times
 	log.Println("\n2. Incrementing counter 5 times...")
 	for i := 0; i < 5; i++ {
-		response, err := c.InvokeActor(ctx, &client.InvokeActorRequest{
-			ActorType: actorType,
-			ActorID:   actorID,
-			Method:    "increment",
-		})
+		state, err = counter.Increment(ctx)
 		if err != nil {
 			log.Fatalf("Failed to increment counter: %v", err)
 		}
-
-		if err := json.Unmarshal(response.Data, &state); err != nil {
-			log.Fatalf("Failed to unmarshal response: %v", err)
-		}
 		log.Printf("After increment %d: %d", i+1, state.Value)
 		time.Sleep(500 * time.Millisecond)
 	}
@@ -65,89 +49,45 @@ func main() {
 	// Test 3: Decrement counter 2 times
 	log.Println("\n3. Decrementing counter 2 times...")
 	for i := 0; i < 2; i++ {
-		response, err := c.InvokeActor(ctx, &client.InvokeActorRequest{
-			ActorType: actorType,
-			ActorID:   actorID,
-			Method:    "decrement",
-		})
+		state, err = counter.Decrement(ctx)
 		if err != nil {
 			log.Fatalf("Failed to decrement counter: %v", err)
 		}
-
-		if err := json.Unmarshal(response.Data, &state); err != nil {
-			log.Fatalf("Failed to unmarshal response: %v", err)
-		}
 		log.Printf("After decrement %d: %d", i+1, state.Value)
 		time.Sleep(500 * time.Millisecond)
 	}
 
 	// Test 4: Set counter to specific value
 	log.Println("\n4. Setting counter to 100...")
-	setValue := map[string]int{"value": 100}
-	setData, _ := json.Marshal(setValue)
-
-	response, err = c.InvokeActor(ctx, &client.InvokeActorRequest{
-		ActorType: actorType,
-		ActorID:   actorID,
-		Method:    "set",
-		Data:      setData,
-	})
+	state, err = counter.Set(ctx, counteractor.SetValueRequest{Value: 100})
 	if err != nil {
 		log.Fatalf("Failed to set counter value: %v", err)
 	}
-
-	if err := json.Unmarshal(response.Data, &state); err != nil {
-		log.Fatalf("Failed to unmarshal response: %v", err)
-	}
 	log.Printf("After setting to 100: %d", state.Value)
 
 	// Test 5: Final value check
 	log.Println("\n5. Getting final counter value...")
-	response, err = c.InvokeActor(ctx, &client.InvokeActorRequest{
-		ActorType: actorType,
-		ActorID:   actorID,
-		Method:    "get",
-	})
+	state, err = counter.Get(ctx)
 	if err != nil {
 		log.Fatalf("Failed to get final counter value: %v", err)
 	}
-
-	if err := json.Unmarshal(response.Data, &state); err != nil {
-		log.Fatalf("Failed to unmarshal response: %v", err)
-	}
 	log.Printf("Final value: %d", state.Value)
 
 	// Test 6: Test with different actor instance
 	log.Println("\n6. Testing with different actor instance (counter-2)...")
-	actorID2 := "counter-2"
-	
-	response, err = c.InvokeActor(ctx, &client.InvokeActorRequest{
-		ActorType: actorType,
-		ActorID:   actorID2,
-		Method:    "get",
-	})
+	counter2 := counteractor.NewCounterActorClient(c, "counter-2")
+
+	state, err = counter2.Get(ctx)
 	if err != nil {
 		log.Fatalf("Failed to get counter-2 value: %v", err)
 	}
-
-	if err := json.Unmarshal(response.Data, &state); err != nil {
-		log.Fatalf("Failed to unmarshal response: %v", err)
-	}
 	log.Printf("Counter-2 initial value: %d", state.Value)
 
 	// Increment counter-2
-	response, err = c.InvokeActor(ctx, &client.InvokeActorRequest{
-		ActorType: actorType,
-		ActorID:   actorID2,
-		Method:    "increment",
-	})
+	state, err = counter2.Increment(ctx)
 	if err != nil {
 		log.Fatalf("Failed to increment counter-2: %v", err)
 	}
-
-	if err := json.Unmarshal(response.Data, &state); err != nil {
-		log.Fatalf("Failed to unmarshal response: %v", err)
-	}
 	log.Printf("Counter-2 after increment: %d", state.Value)
 
 	log.Println("\n=== Demo completed successfully! ===")
@@ -155,4 +95,4 @@ func main() {
 	log.Println("- Actor state persistence")
 	log.Println("- Method invocation")
 	log.Println("- Multiple actor instances with independent state")
-}
\ No newline at end of file
+}
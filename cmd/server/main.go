@@ -3,14 +3,18 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 
+	"github.com/dapr/go-sdk/actor"
 	"github.com/dapr/go-sdk/service/common"
 	daprd "github.com/dapr/go-sdk/service/http"
 	
 	"github.com/shogotsuneto/dapr-actor-experiment/internal/bankaccountactor"
-	"github.com/shogotsuneto/dapr-actor-experiment/internal/counteractor"
+	"github.com/shogotsuneto/dapr-actor-experiment/internal/registry"
 )
 
 // healthHandler provides a simple health check endpoint
@@ -24,15 +28,19 @@ func healthHandler(ctx context.Context, in *common.InvocationEvent) (out *common
 
 // statusHandler provides status information about the actor service
 func statusHandler(ctx context.Context, in *common.InvocationEvent) (out *common.Content, err error) {
+	// Actor types and their persistence patterns come from the generated registry
+	patterns := make(map[string]string)
+	for _, a := range registry.Actors {
+		patterns[a.ActorType] = a.Persistence
+	}
+
 	response := map[string]interface{}{
 		"status":      "running",
 		"service":     "dapr-actor-demo",
-		"actor_types": []string{counteractor.ActorTypeCounterActor, bankaccountactor.ActorTypeBankAccountActor},
+		"actor_types": registry.ActorTypes(),
 		"description": "Multi-actor service demonstrating state-based and event-sourced patterns",
-		"patterns": map[string]string{
-			counteractor.ActorTypeCounterActor:     "State-based - stores current value only",
-			bankaccountactor.ActorTypeBankAccountActor: "Event-sourced - stores events and computes state",
-		},
+		"patterns":    patterns,
+		"actors":      registry.Actors,
 	}
 	
 	data, _ := json.Marshal(response)
@@ -43,17 +51,44 @@ func statusHandler(ctx context.Context, in *common.InvocationEvent) (out *common
 	return
 }
 
+// snapshotPolicy returns the BankAccountActor snapshot policy from BANK_ACCOUNT_SNAPSHOT_EVENTS and
+// BANK_ACCOUNT_SNAPSHOT_BYTES. Unset variables keep the defaults, and 0 disables a threshold.
+func snapshotPolicy() (bankaccountactor.SnapshotPolicy, error) {
+	policy := bankaccountactor.DefaultSnapshotPolicy()
+	thresholds := []struct {
+		name      string
+		threshold *int
+	}{
+		{"BANK_ACCOUNT_SNAPSHOT_EVENTS", &policy.EveryEvents},
+		{"BANK_ACCOUNT_SNAPSHOT_BYTES", &policy.EveryBytes},
+	}
+	for _, t := range thresholds {
+		value := os.Getenv(t.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return policy, fmt.Errorf("invalid %s %q: expected a non-negative integer", t.name, value)
+		}
+		*t.threshold = n
+	}
+	return policy, nil
+}
+
 func main() {
+	snapshots, err := snapshotPolicy()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	
 	// Create Dapr service
 	s := daprd.NewService(":8080")
 	
-	// Register CounterActor using generated factory with contract enforcement
-	log.Printf("Registering %s with state-based pattern", counteractor.ActorTypeCounterActor)
-	s.RegisterActorImplFactoryContext(counteractor.NewActorFactory())
-	
-	// Register BankAccountActor using generated factory with contract enforcement
-	log.Printf("Registering %s with event sourcing pattern", bankaccountactor.ActorTypeBankAccountActor)
-	s.RegisterActorImplFactoryContext(bankaccountactor.NewActorFactory())
+	// Register every actor type in the OpenAPI schema using its generated factory with contract enforcement
+	registry.RegisterAll(s, map[string]func() actor.ServerContext{
+		bankaccountactor.ActorTypeBankAccountActor: bankaccountactor.NewActorFactory(bankaccountactor.WithSnapshots(snapshots)),
+	})
 	
 	// Add health and status endpoints
 	s.AddServiceInvocationHandler("/health", healthHandler)
@@ -61,8 +96,11 @@ func main() {
 	
 	log.Println("Starting Multi-Actor Dapr Service on port 8080...")
 	log.Printf("Actors registered:")
-	log.Printf("  - %s: State-based counter operations", counteractor.ActorTypeCounterActor)
-	log.Printf("  - %s: Event-sourced bank account with full audit trail", bankaccountactor.ActorTypeBankAccountActor)
+	for _, a := range registry.Actors {
+		log.Printf("  - %s: %s", a.ActorType, a.Description)
+	}
+	log.Printf("BankAccountActor snapshots: every %d events, every %d bytes (0 = disabled)",
+		snapshots.EveryEvents, snapshots.EveryBytes)
 	
 	// Start the service
 	if err := s.Start(); err != nil && err != http.ErrServerClosed {
@@ -6,6 +6,8 @@ package bankaccountactor
 import (
 	"context"
 	"github.com/dapr/go-sdk/actor"
+	"regexp"
+	"unicode/utf8"
 )
 
 // ActorTypeBankAccountActor is the Dapr actor type identifier for BankAccountActor
@@ -15,14 +17,30 @@ const ActorTypeBankAccountActor = "BankAccountActor"
 // This interface enforces compile-time schema compliance and includes actor.ServerContext for proper Dapr actor implementation.
 type BankAccountActorAPI interface {
 	actor.ServerContext
-	// Withdraw money from account
-	Withdraw(ctx context.Context, request WithdrawRequest) (*BankAccountState, error)
-	// Get transaction history
-	GetHistory(ctx context.Context) (*TransactionHistory, error)
 	// Create new bank account
 	CreateAccount(ctx context.Context, request CreateAccountRequest) (*BankAccountState, error)
 	// Deposit money to account
 	Deposit(ctx context.Context, request DepositRequest) (*BankAccountState, error)
 	// Get current account balance
 	GetBalance(ctx context.Context) (*BankAccountState, error)
-}
\ No newline at end of file
+	// Get transaction history
+	GetHistory(ctx context.Context) (*TransactionHistory, error)
+	// Withdraw money from account
+	Withdraw(ctx context.Context, request WithdrawRequest) (*BankAccountState, error)
+}
+
+var actorIDPattern = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+// ValidateActorID checks an actor ID against the actorId parameter constraints declared in the OpenAPI schema.
+func ValidateActorID(id string) error {
+	if id == "" {
+		return &ValidationError{Field: "actorId", Message: "must not be empty"}
+	}
+	if utf8.RuneCountInString(id) > 50 {
+		return &ValidationError{Field: "actorId", Message: "must be at most 50 characters"}
+	}
+	if !actorIDPattern.MatchString(id) {
+		return &ValidationError{Field: "actorId", Message: "must match pattern ^[a-zA-Z0-9_-]+$"}
+	}
+	return nil
+}
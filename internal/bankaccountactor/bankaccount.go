@@ -2,8 +2,9 @@ package bankaccountactor
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,16 @@ import (
 )
 
 // BankAccountActor demonstrates event sourcing pattern with in-memory state caching.
+// Request bodies are validated against the OpenAPI schema by the generated BankAccountActorServer
+// wrapper, so command methods only enforce business rules (e.g. sufficient funds). Business rule
+// violations are returned as the error types declared in the schema (e.g. *InsufficientFundsError),
+// which the wrapper carries to generated clients as typed errors.
+// The account version is the sequence number of its last event. Commands with an expectedVersion
+// fail with *VersionConflictError unless the account is still at that version, which lets clients
+// detect updates made since they read the account.
+// Deposit and Withdraw requests with an idempotencyKey are applied once: a retry with the same key
+// returns the result of the original request, and reusing the key for another request fails with
+// *IdempotencyKeyConflictError.
 // This actor stores events for durability and audit trail, while maintaining fast access
 // through ephemeral in-memory state cache as long as the actor is activated.
 //
@@ -27,45 +38,165 @@ import (
 // - After: State loaded once, operations use cached state = O(1) access time
 type BankAccountActor struct {
 	actor.ServerImplBaseCtx
-	
+
 	// Ephemeral in-memory state for fast access (cached from events)
-	cachedState    *BankAccountState
-	stateLoaded    bool  // Track if state has been loaded from events
-	accountExists  bool  // Track if account exists to avoid repeated checks
+	cachedState   *BankAccountState
+	stateLoaded   bool // Track if state has been loaded from events
+	accountExists bool // Track if account exists to avoid repeated checks
+
+	// Cached head of the event log
+	headSequence   int64 // Sequence number of the last stored event
+	eventLogLoaded bool  // Track if the head has been read (and legacy events migrated)
+
+	// Events appended since the last snapshot, compared against the snapshot policy
+	snapshots          *SnapshotPolicy // Set by WithSnapshots; nil uses DefaultSnapshotPolicy
+	snapshotSequence   int64           // Sequence number of the last event covered by the snapshot
+	bytesSinceSnapshot int             // Encoded size of the events after snapshotSequence
+
+	// Window of processed idempotency keys, loaded on first use
+	processed       []processedCommand
+	processedLoaded bool
+}
+
+// SnapshotPolicy decides when BankAccountActor snapshots its state, so that activation loads the
+// snapshot and replays only the events after it. A snapshot is taken when the events appended since
+// the last one reach either threshold; a zero threshold is not checked.
+type SnapshotPolicy struct {
+	EveryEvents int // Number of events
+	EveryBytes  int // Encoded size of the events in bytes
+}
+
+// DefaultSnapshotPolicy returns the policy of instances created without WithSnapshots: a snapshot
+// every 100 events.
+func DefaultSnapshotPolicy() SnapshotPolicy {
+	return SnapshotPolicy{EveryEvents: 100}
+}
+
+// WithSnapshots is a NewActorFactory option that sets the snapshot policy of the instances.
+func WithSnapshots(policy SnapshotPolicy) func(*BankAccountActor) {
+	return func(b *BankAccountActor) {
+		b.snapshots = &policy
+	}
 }
 
-// Event types
+// State keys of the event log. Each event is stored under its own key, eventKeyPrefix followed by
+// its zero-padded sequence number (events/000042), and headKey holds the sequence number of the
+// last event. Appending an event writes only the new event and the head, which Dapr saves in a
+// single state transaction when the method returns.
 const (
-	AccountCreatedEvent  = "AccountCreated"
-	MoneyDepositedEvent  = "MoneyDeposited"
-	MoneyWithdrawnEvent  = "MoneyWithdrawn"
+	eventKeyPrefix  = "events/"
+	headKey         = "events/head"
+	legacyEventsKey = "events" // Whole event array, the layout before per-event keys
 )
 
-// Internal event structures (not exposed in API)
-type AccountCreatedEventData struct {
-	OwnerName      string    `json:"ownerName"`
-	InitialDeposit float64   `json:"initialDeposit"`
-	CreatedAt      time.Time `json:"createdAt"`
+// snapshotKey holds the latest snapshot of the account state, next to the event log.
+const snapshotKey = "snapshot"
+
+// snapshotVersion is the format version of stored snapshots. Increment it whenever
+// BankAccountState or the way events are applied to it changes: snapshots of other versions are
+// ignored and the state is replayed from the events, and the next snapshot replaces them.
+const snapshotVersion = 1
+
+// accountSnapshot is the value stored under snapshotKey. State is kept encoded so that snapshots
+// of another version are discarded without being decoded.
+type accountSnapshot struct {
+	Version  int             `json:"version"`
+	Sequence int64           `json:"sequence"` // Last event applied to State
+	State    json.RawMessage `json:"state"`
+}
+
+// idempotencyStateKey holds the window of processed idempotency keys. It is written in the same
+// transaction as the event of each command that carries a key.
+const idempotencyStateKey = "idempotency"
+
+// idempotencyWindow is the number of most recent idempotency keys an account remembers. A retry
+// with an older key is applied again.
+const idempotencyWindow = 100
+
+// processedCommand is a command with an idempotency key, kept in the window under idempotencyStateKey.
+// A retry must be the same method with the same request.
+type processedCommand struct {
+	Key         string           `json:"key"`
+	Command     string           `json:"command"`     // Method, e.g. "Deposit"
+	Fingerprint string           `json:"fingerprint"` // requestFingerprint of the request
+	Sequence    int64            `json:"sequence"`    // Event stored by the command
+	Result      BankAccountState `json:"result"`      // Result returned to retries
+}
+
+// newProcessedCommand returns the command to look up and record for a request of method command,
+// or nil when the request has no idempotency key.
+func newProcessedCommand(command string, key *string, request interface{}) (*processedCommand, error) {
+	if key == nil {
+		return nil, nil
+	}
+	fingerprint, err := requestFingerprint(request)
+	if err != nil {
+		return nil, err
+	}
+	return &processedCommand{Key: *key, Command: command, Fingerprint: fingerprint}, nil
+}
+
+// requestFingerprint returns the SHA-256 hash of the JSON encoding of a request.
+func requestFingerprint(request interface{}) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
 }
 
-type MoneyDepositedEventData struct {
-	Amount      float64   `json:"amount"`
-	Description string    `json:"description"`
-	Timestamp   time.Time `json:"timestamp"`
+// eventLogHead is the value stored under headKey.
+type eventLogHead struct {
+	Sequence int64 `json:"sequence"`
 }
 
-type MoneyWithdrawnEventData struct {
-	Amount      float64   `json:"amount"`
-	Description string    `json:"description"`
-	Timestamp   time.Time `json:"timestamp"`
+// eventKey returns the state key of the event with the given sequence number.
+func eventKey(sequence int64) string {
+	return fmt.Sprintf("%s%06d", eventKeyPrefix, sequence)
 }
 
-// StoredEvent represents an event as stored in the state store
+// StoredEvent represents an event as stored in the state store.
+// Event types and payloads are the AccountEventType and AccountEventData types generated from the OpenAPI schema.
+// SchemaVersion is the version of the payload's shape; older payloads are upcast when read (see events.go).
 type StoredEvent struct {
-	EventID   string      `json:"eventId"`
-	EventType string      `json:"eventType"`
-	Timestamp time.Time   `json:"timestamp"`
-	Data      interface{} `json:"data"`
+	EventID       uuid.UUID        `json:"eventId"`
+	EventType     AccountEventType `json:"eventType"`
+	SchemaVersion int              `json:"schemaVersion"`
+	Timestamp     time.Time        `json:"timestamp"`
+	Data          AccountEventData `json:"data"`
+
+	// Idempotency key of the command that stored the event, recorded for auditing
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
+}
+
+// UnmarshalJSON upcasts Data to the current schema version of the event type and decodes it by
+// the event's EventType, so events stored before the payload carried its own eventType
+// discriminator can still be read. Events without a schemaVersion are legacySchemaVersion.
+func (e *StoredEvent) UnmarshalJSON(data []byte) error {
+	type plain StoredEvent
+	var raw struct {
+		*plain
+		Data json.RawMessage `json:"data"`
+	}
+	raw.plain = (*plain)(e)
+	e.SchemaVersion = legacySchemaVersion
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	payload, err := upcastEvent(e.EventType, e.SchemaVersion, raw.Data)
+	if err != nil {
+		return err
+	}
+	e.SchemaVersion, _ = currentSchemaVersion(e.EventType)
+
+	eventData, err := UnmarshalAccountEventDataAs(string(e.EventType), payload)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s event: %v", e.EventType, err)
+	}
+	e.Data = eventData
+	return nil
 }
 
 func (b *BankAccountActor) Type() string {
@@ -74,20 +205,20 @@ func (b *BankAccountActor) Type() string {
 
 // ensureStateLoaded loads and caches state from events if not already loaded.
 // This provides fast in-memory access while maintaining event sourcing benefits.
-// 
+//
 // PERFORMANCE: This method implements lazy loading - state is computed from events
 // only once when the actor is first accessed, then cached for subsequent operations.
 func (b *BankAccountActor) ensureStateLoaded(ctx context.Context) error {
 	if b.stateLoaded {
 		return nil // State already loaded and cached - fast path!
 	}
-	
+
 	// Load state from events for the first time (expensive operation)
 	state, err := b.computeStateFromEvents(ctx)
 	if err != nil {
 		return err
 	}
-	
+
 	if state == nil {
 		// Account doesn't exist yet
 		b.accountExists = false
@@ -97,16 +228,29 @@ func (b *BankAccountActor) ensureStateLoaded(ctx context.Context) error {
 		b.accountExists = true
 		b.cachedState = state
 	}
-	
+
 	b.stateLoaded = true
 	return nil
 }
 
+// checkExpectedVersion returns a *VersionConflictError when expectedVersion is set and differs
+// from the current version of the account.
+func (b *BankAccountActor) checkExpectedVersion(expectedVersion *int64) error {
+	if expectedVersion == nil || *expectedVersion == b.headSequence {
+		return nil
+	}
+	return &VersionConflictError{
+		Message:         fmt.Sprintf("version conflict: expected version %d, current version %d", *expectedVersion, b.headSequence),
+		ExpectedVersion: *expectedVersion,
+		CurrentVersion:  b.headSequence,
+	}
+}
+
 // getCachedState returns the in-memory cached state for fast O(1) access.
 // This leverages the actor pattern's stateful nature for optimal performance.
 func (b *BankAccountActor) getCachedState() (*BankAccountState, error) {
 	if !b.accountExists {
-		return nil, errors.New("account does not exist - create account first")
+		return nil, &AccountNotFoundError{Message: "account does not exist - create account first"}
 	}
 	return b.cachedState, nil
 }
@@ -116,109 +260,106 @@ func (b *BankAccountActor) CreateAccount(ctx context.Context, request CreateAcco
 	if err := b.ensureStateLoaded(ctx); err != nil {
 		return nil, err
 	}
-	
+
 	// Check if account already exists (fast in-memory check)
 	if b.accountExists {
-		return nil, errors.New("account already exists")
-	}
-	
-	// Validate request
-	if request.OwnerName == "" {
-		return nil, errors.New("owner name is required")
+		return nil, &AccountAlreadyExistsError{Message: "account already exists"}
 	}
-	if request.InitialDeposit < 0 {
-		return nil, errors.New("initial deposit cannot be negative")
+	if err := b.checkExpectedVersion(request.ExpectedVersion); err != nil {
+		return nil, err
 	}
-	
-	// Create and store event for durability
+
+	// Store the event for durability; appendEvent also applies it to the cached state
 	eventData := AccountCreatedEventData{
 		OwnerName:      request.OwnerName,
 		InitialDeposit: request.InitialDeposit,
 		CreatedAt:      time.Now(),
 	}
-	
-	if err := b.appendEvent(ctx, AccountCreatedEvent, eventData); err != nil {
+
+	if err := b.appendEvent(ctx, AccountEventTypeAccountCreated, eventData, nil); err != nil {
 		return nil, err
 	}
-	
-	// Update in-memory cached state for fast access
-	b.cachedState = &BankAccountState{
-		AccountId: b.ID(),
-		OwnerName: request.OwnerName,
-		Balance:   request.InitialDeposit,
-		IsActive:  true,
-		CreatedAt: eventData.CreatedAt.Format(time.RFC3339),
-	}
-	b.accountExists = true
-	
+
 	return b.cachedState, nil
 }
 
 func (b *BankAccountActor) Deposit(ctx context.Context, request DepositRequest) (*BankAccountState, error) {
-	// Validate request
-	if request.Amount <= 0 {
-		return nil, errors.New("deposit amount must be positive")
-	}
-	
 	// Ensure state is loaded and account exists
 	if err := b.ensureStateLoaded(ctx); err != nil {
 		return nil, err
 	}
+
+	// A retry returns the original result before any check, as the account has moved on since
+	command, err := newProcessedCommand("Deposit", request.IdempotencyKey, request)
+	if err != nil {
+		return nil, err
+	}
+	if result, err := b.processedResult(ctx, command); result != nil || err != nil {
+		return result, err
+	}
 	if _, err := b.getCachedState(); err != nil {
 		return nil, err
 	}
-	
-	// Create and store event for durability
+	if err := b.checkExpectedVersion(request.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	// Store the event for durability; appendEvent also applies it to the cached state
 	eventData := MoneyDepositedEventData{
 		Amount:      request.Amount,
 		Description: request.Description,
 		Timestamp:   time.Now(),
 	}
-	
-	if err := b.appendEvent(ctx, MoneyDepositedEvent, eventData); err != nil {
+
+	if err := b.appendEvent(ctx, AccountEventTypeMoneyDeposited, eventData, command); err != nil {
 		return nil, err
 	}
-	
-	// Update in-memory cached state for fast access
-	b.cachedState.Balance += request.Amount
-	
+
 	return b.cachedState, nil
 }
 
 func (b *BankAccountActor) Withdraw(ctx context.Context, request WithdrawRequest) (*BankAccountState, error) {
-	// Validate request
-	if request.Amount <= 0 {
-		return nil, errors.New("withdrawal amount must be positive")
-	}
-	
 	// Ensure state is loaded and account exists
 	if err := b.ensureStateLoaded(ctx); err != nil {
 		return nil, err
 	}
+
+	// A retry returns the original result before any check, as the account has moved on since
+	command, err := newProcessedCommand("Withdraw", request.IdempotencyKey, request)
+	if err != nil {
+		return nil, err
+	}
+	if result, err := b.processedResult(ctx, command); result != nil || err != nil {
+		return result, err
+	}
 	currentState, err := b.getCachedState()
 	if err != nil {
 		return nil, err
 	}
-	
+	if err := b.checkExpectedVersion(request.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
 	// Check sufficient balance using fast in-memory state
 	if currentState.Balance < request.Amount {
-		return nil, fmt.Errorf("insufficient funds: balance %.2f, requested %.2f", currentState.Balance, request.Amount)
+		return nil, &InsufficientFundsError{
+			Message:   fmt.Sprintf("insufficient funds: balance %.2f, requested %.2f", currentState.Balance, request.Amount),
+			Balance:   currentState.Balance,
+			Requested: request.Amount,
+		}
 	}
-	
-	// Create and store event for durability
+
+	// Store the event for durability; appendEvent also applies it to the cached state
 	eventData := MoneyWithdrawnEventData{
 		Amount:      request.Amount,
 		Description: request.Description,
 		Timestamp:   time.Now(),
 	}
-	
-	if err := b.appendEvent(ctx, MoneyWithdrawnEvent, eventData); err != nil {
+
+	if err := b.appendEvent(ctx, AccountEventTypeMoneyWithdrawn, eventData, command); err != nil {
 		return nil, err
 	}
-	
-	// Update in-memory cached state for fast access
-	b.cachedState.Balance -= request.Amount
-	
+
 	return b.cachedState, nil
 }
 
@@ -227,7 +368,7 @@ func (b *BankAccountActor) GetBalance(ctx context.Context) (*BankAccountState, e
 	if err := b.ensureStateLoaded(ctx); err != nil {
 		return nil, err
 	}
-	
+
 	// Return fast in-memory cached state
 	return b.getCachedState()
 }
@@ -237,28 +378,28 @@ func (b *BankAccountActor) GetHistory(ctx context.Context) (*TransactionHistory,
 	if err := b.ensureStateLoaded(ctx); err != nil {
 		return nil, err
 	}
-	if !b.accountExists {
-		return nil, errors.New("account does not exist - create account first")
+	if _, err := b.getCachedState(); err != nil {
+		return nil, err
 	}
-	
+
 	// Get events for history (still need to read from storage for complete audit trail)
 	events, err := b.getAllEvents(ctx)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	// Convert internal events to API events
-	var apiEvents []interface{}
+	apiEvents := make([]AccountEvent, 0, len(events))
 	for _, event := range events {
-		apiEvent := AccountEvent{
-			EventId:   event.EventID,
-			EventType: event.EventType,
-			Timestamp: event.Timestamp.Format(time.RFC3339),
-			Data:      b.convertEventDataToMap(event.Data),
-		}
-		apiEvents = append(apiEvents, apiEvent)
+		apiEvents = append(apiEvents, AccountEvent{
+			EventId:        event.EventID,
+			EventType:      event.EventType,
+			Timestamp:      event.Timestamp,
+			Data:           event.Data,
+			IdempotencyKey: event.IdempotencyKey,
+		})
 	}
-	
+
 	return &TransactionHistory{
 		AccountId: b.ID(),
 		Events:    apiEvents,
@@ -267,127 +408,347 @@ func (b *BankAccountActor) GetHistory(ctx context.Context) (*TransactionHistory,
 
 // Event sourcing implementation details
 
-func (b *BankAccountActor) appendEvent(ctx context.Context, eventType string, eventData interface{}) error {
+// appendEvent stores an event under the next sequence number, advances the head and applies the
+// event to the cached state, snapshotting it when the snapshot policy is due and recording command,
+// the command with an idempotency key if any. Only these keys are written, so the cost of an append
+// does not grow with the length of the log. Every step that can fail runs before the first write:
+// Dapr does not save the state of a failed call, but keeps its pending writes and saves them with
+// the next successful one, so a failed append must not leave any behind.
+func (b *BankAccountActor) appendEvent(ctx context.Context, eventType AccountEventType, eventData AccountEventData, command *processedCommand) error {
+	if err := b.ensureEventLog(ctx); err != nil {
+		return err
+	}
+	if command != nil {
+		if err := b.ensureProcessedLoaded(ctx); err != nil {
+			return err
+		}
+	}
+
+	schemaVersion, err := currentSchemaVersion(eventType)
+	if err != nil {
+		return err
+	}
 	event := StoredEvent{
-		EventID:   uuid.New().String(),
-		EventType: eventType,
-		Timestamp: time.Now(),
-		Data:      eventData,
+		EventID:       uuid.New(),
+		EventType:     eventType,
+		SchemaVersion: schemaVersion,
+		Timestamp:     time.Now(),
+		Data:          eventData,
 	}
-	
-	// Load existing events
-	events, err := b.getAllEvents(ctx)
+	if command != nil {
+		event.IdempotencyKey = &command.Key
+	}
+
+	// Apply the event to a copy, so the cached state is untouched if it cannot be applied
+	var state *BankAccountState
+	if b.cachedState != nil {
+		current := *b.cachedState
+		state = &current
+	}
+	if state, err = b.applyEvent(state, event); err != nil {
+		return err
+	}
+	sequence := b.headSequence + 1
+	state.Version = sequence
+
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	bytesSinceSnapshot := b.bytesSinceSnapshot + len(encoded)
+	snapshot, err := b.dueSnapshot(sequence, bytesSinceSnapshot, state)
 	if err != nil {
 		return err
 	}
-	
-	// Append new event
-	events = append(events, event)
-	
-	// Store back to state manager
-	eventsKey := "events"
-	return b.GetStateManager().Set(ctx, eventsKey, events)
+
+	window := b.processed
+	if command != nil {
+		window = b.processedWindow(command, sequence, state)
+	}
+
+	// The writes are saved together by Dapr's transactional state save. Set only rejects an empty
+	// state name, so none of them fails once the first is staged.
+	if err := b.GetStateManager().Set(ctx, eventKey(sequence), event); err != nil {
+		return err
+	}
+	if err := b.GetStateManager().Set(ctx, headKey, eventLogHead{Sequence: sequence}); err != nil {
+		return err
+	}
+	if command != nil {
+		if err := b.GetStateManager().Set(ctx, idempotencyStateKey, window); err != nil {
+			return err
+		}
+	}
+	if snapshot != nil {
+		if err := b.GetStateManager().Set(ctx, snapshotKey, snapshot); err != nil {
+			return err
+		}
+	}
+
+	b.headSequence = sequence
+	b.cachedState = state
+	b.accountExists = true
+	b.processed = window
+	b.bytesSinceSnapshot = bytesSinceSnapshot
+	if snapshot != nil {
+		b.snapshotSequence = sequence
+		b.bytesSinceSnapshot = 0
+	}
+	return nil
 }
 
 func (b *BankAccountActor) getAllEvents(ctx context.Context) ([]StoredEvent, error) {
-	eventsKey := "events"
-	var events []StoredEvent
-	
-	ok, err := b.GetStateManager().Contains(ctx, eventsKey)
-	if err != nil {
+	return b.getEvents(ctx, 1)
+}
+
+// getEvents reads the events from sequence number from up to the head of the log.
+func (b *BankAccountActor) getEvents(ctx context.Context, from int64) ([]StoredEvent, error) {
+	if err := b.ensureEventLog(ctx); err != nil {
 		return nil, err
 	}
-	
-	if !ok {
-		return []StoredEvent{}, nil
+
+	events := make([]StoredEvent, 0, max(b.headSequence-from+1, 0))
+	for sequence := from; sequence <= b.headSequence; sequence++ {
+		var event StoredEvent
+		if err := b.GetStateManager().Get(ctx, eventKey(sequence), &event); err != nil {
+			return nil, fmt.Errorf("failed to read event %d: %v", sequence, err)
+		}
+		events = append(events, event)
 	}
-	
-	err = b.GetStateManager().Get(ctx, eventsKey, &events)
+
+	return events, nil
+}
+
+// ensureEventLog reads the head of the event log once per activation. Accounts stored in the
+// legacy single-key layout are migrated first.
+func (b *BankAccountActor) ensureEventLog(ctx context.Context) error {
+	if b.eventLogLoaded {
+		return nil
+	}
+
+	ok, err := b.GetStateManager().Contains(ctx, headKey)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	
-	return events, nil
+
+	if ok {
+		var head eventLogHead
+		if err := b.GetStateManager().Get(ctx, headKey, &head); err != nil {
+			return err
+		}
+		b.headSequence = head.Sequence
+	} else if err := b.migrateLegacyEvents(ctx); err != nil {
+		return err
+	}
+
+	b.eventLogLoaded = true
+	return nil
 }
 
+// migrateLegacyEvents moves the events of an account stored as one array under legacyEventsKey to
+// per-event keys and removes the array. The changes are saved in the same transaction as the rest
+// of the call, so an account is never left half migrated.
+func (b *BankAccountActor) migrateLegacyEvents(ctx context.Context) error {
+	ok, err := b.GetStateManager().Contains(ctx, legacyEventsKey)
+	if err != nil || !ok {
+		return err
+	}
+
+	var events []StoredEvent
+	if err := b.GetStateManager().Get(ctx, legacyEventsKey, &events); err != nil {
+		return fmt.Errorf("failed to read legacy events: %v", err)
+	}
+
+	for i, event := range events {
+		if err := b.GetStateManager().Set(ctx, eventKey(int64(i+1)), event); err != nil {
+			return err
+		}
+	}
+	b.headSequence = int64(len(events))
+	if err := b.GetStateManager().Set(ctx, headKey, eventLogHead{Sequence: b.headSequence}); err != nil {
+		return err
+	}
+
+	return b.GetStateManager().Remove(ctx, legacyEventsKey)
+}
+
+// computeStateFromEvents loads the latest usable snapshot and replays the events after it.
+// It returns nil when the account has not been created.
 func (b *BankAccountActor) computeStateFromEvents(ctx context.Context) (*BankAccountState, error) {
-	events, err := b.getAllEvents(ctx)
+	if err := b.ensureEventLog(ctx); err != nil {
+		return nil, err
+	}
+
+	state, err := b.loadSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replay the events after the snapshot
+	events, err := b.getEvents(ctx, b.snapshotSequence+1)
 	if err != nil {
 		return nil, err
 	}
-	
-	if len(events) == 0 {
-		return nil, nil // Account doesn't exist
+	for i, event := range events {
+		if state, err = b.applyEvent(state, event); err != nil {
+			return nil, fmt.Errorf("failed to replay event %d: %v", b.snapshotSequence+1+int64(i), err)
+		}
+
+		encoded, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		b.bytesSinceSnapshot += len(encoded)
 	}
-	
-	// Initialize state
-	state := &BankAccountState{
-		AccountId: b.ID(),
-		Balance:   0,
-		IsActive:  true,
+	if state != nil {
+		state.Version = b.headSequence
 	}
-	
-	// Replay events to compute current state
-	for _, event := range events {
-		switch event.EventType {
-		case AccountCreatedEvent:
-			data, err := b.parseEventData(event.Data, &AccountCreatedEventData{})
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse AccountCreated event: %v", err)
-			}
-			createdData := data.(*AccountCreatedEventData)
-			state.OwnerName = createdData.OwnerName
-			state.Balance = createdData.InitialDeposit
-			state.CreatedAt = createdData.CreatedAt.Format(time.RFC3339)
-			
-		case MoneyDepositedEvent:
-			data, err := b.parseEventData(event.Data, &MoneyDepositedEventData{})
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse MoneyDeposited event: %v", err)
-			}
-			depositData := data.(*MoneyDepositedEventData)
-			state.Balance += depositData.Amount
-			
-		case MoneyWithdrawnEvent:
-			data, err := b.parseEventData(event.Data, &MoneyWithdrawnEventData{})
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse MoneyWithdrawn event: %v", err)
-			}
-			withdrawData := data.(*MoneyWithdrawnEventData)
-			state.Balance -= withdrawData.Amount
+
+	return state, nil
+}
+
+// applyEvent returns the account state after event. state is nil before the account is created.
+// Events of a type it does not handle are an error, so replay never silently skips an event.
+func (b *BankAccountActor) applyEvent(state *BankAccountState, event StoredEvent) (*BankAccountState, error) {
+	if state == nil {
+		state = &BankAccountState{
+			AccountId: b.ID(),
+			Balance:   0,
+			IsActive:  true,
 		}
 	}
-	
+
+	switch data := event.Data.(type) {
+	case AccountCreatedEventData:
+		state.OwnerName = data.OwnerName
+		state.Balance = data.InitialDeposit
+		state.CreatedAt = &data.CreatedAt
+
+	case MoneyDepositedEventData:
+		state.Balance += data.Amount
+
+	case MoneyWithdrawnEventData:
+		state.Balance -= data.Amount
+
+	default:
+		return nil, fmt.Errorf("unknown %s event data %T", event.EventType, event.Data)
+	}
+
 	return state, nil
 }
 
-func (b *BankAccountActor) parseEventData(data interface{}, target interface{}) (interface{}, error) {
-	// Convert to JSON and back to parse properly
-	jsonData, err := json.Marshal(data)
-	if err != nil {
+// loadSnapshot returns the state in the stored snapshot and sets snapshotSequence to the last event
+// it covers. It returns nil, and the state is replayed from the first event, when there is no
+// snapshot or it cannot be used: written in another format version or ahead of the event log.
+func (b *BankAccountActor) loadSnapshot(ctx context.Context) (*BankAccountState, error) {
+	b.snapshotSequence = 0
+	b.bytesSinceSnapshot = 0
+
+	ok, err := b.GetStateManager().Contains(ctx, snapshotKey)
+	if err != nil || !ok {
 		return nil, err
 	}
-	
-	err = json.Unmarshal(jsonData, target)
-	if err != nil {
+
+	var snapshot accountSnapshot
+	if err := b.GetStateManager().Get(ctx, snapshotKey, &snapshot); err != nil {
 		return nil, err
 	}
-	
-	return target, nil
+	if snapshot.Version != snapshotVersion || snapshot.Sequence > b.headSequence {
+		return nil, nil // Discarded; the next snapshot overwrites it
+	}
+
+	var state BankAccountState
+	if err := json.Unmarshal(snapshot.State, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %v", err)
+	}
+
+	b.snapshotSequence = snapshot.Sequence
+	return &state, nil
 }
 
-func (b *BankAccountActor) convertEventDataToMap(data interface{}) map[string]interface{} {
-	// Convert to JSON and back to get a map
-	jsonData, err := json.Marshal(data)
+// dueSnapshot returns the snapshot of state, the state after the event with the given sequence
+// number, when the events appended since the last snapshot, of bytesSinceSnapshot encoded bytes,
+// reach a threshold of the snapshot policy. It returns nil when no snapshot is due. The snapshot is
+// saved in the same transaction as the event that triggered it.
+func (b *BankAccountActor) dueSnapshot(sequence int64, bytesSinceSnapshot int, state *BankAccountState) (*accountSnapshot, error) {
+	policy := DefaultSnapshotPolicy()
+	if b.snapshots != nil {
+		policy = *b.snapshots
+	}
+	eventsDue := policy.EveryEvents > 0 && sequence-b.snapshotSequence >= int64(policy.EveryEvents)
+	bytesDue := policy.EveryBytes > 0 && bytesSinceSnapshot >= policy.EveryBytes
+	if !eventsDue && !bytesDue {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(state)
 	if err != nil {
-		return map[string]interface{}{"error": "failed to convert event data"}
+		return nil, err
+	}
+	return &accountSnapshot{
+		Version:  snapshotVersion,
+		Sequence: sequence,
+		State:    encoded,
+	}, nil
+}
+
+// processedResult returns the result of the command that already used the key of command, or nil
+// when command is nil or its key is not in the window. A key used by another method or with another
+// request is an *IdempotencyKeyConflictError.
+func (b *BankAccountActor) processedResult(ctx context.Context, command *processedCommand) (*BankAccountState, error) {
+	if command == nil {
+		return nil, nil
+	}
+	if err := b.ensureProcessedLoaded(ctx); err != nil {
+		return nil, err
 	}
-	
-	var result map[string]interface{}
-	err = json.Unmarshal(jsonData, &result)
+
+	for _, processed := range b.processed {
+		if processed.Key != command.Key {
+			continue
+		}
+		if processed.Command != command.Command || processed.Fingerprint != command.Fingerprint {
+			return nil, &IdempotencyKeyConflictError{
+				Message:        fmt.Sprintf("idempotency key %s was already used by a different request", command.Key),
+				IdempotencyKey: command.Key,
+			}
+		}
+		result := processed.Result
+		return &result, nil
+	}
+	return nil, nil
+}
+
+// processedWindow returns the window of processed keys with command, which stored the event with
+// the given sequence number and left the account in state, added and the oldest keys beyond
+// idempotencyWindow dropped. The window must be loaded.
+func (b *BankAccountActor) processedWindow(command *processedCommand, sequence int64, state *BankAccountState) []processedCommand {
+	window := append(make([]processedCommand, 0, len(b.processed)+1), b.processed...)
+	processed := *command
+	processed.Sequence = sequence
+	processed.Result = *state
+	window = append(window, processed)
+	if len(window) > idempotencyWindow {
+		window = window[len(window)-idempotencyWindow:]
+	}
+	return window
+}
+
+func (b *BankAccountActor) ensureProcessedLoaded(ctx context.Context) error {
+	if b.processedLoaded {
+		return nil
+	}
+
+	ok, err := b.GetStateManager().Contains(ctx, idempotencyStateKey)
 	if err != nil {
-		return map[string]interface{}{"error": "failed to parse event data"}
+		return err
+	}
+	if ok {
+		if err := b.GetStateManager().Get(ctx, idempotencyStateKey, &b.processed); err != nil {
+			return err
+		}
 	}
-	
-	return result
-}
\ No newline at end of file
+
+	b.processedLoaded = true
+	return nil
+}
@@ -0,0 +1,561 @@
+package bankaccountactor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestActor activates a BankAccountActor with its state in store, configured with the
+// NewActorFactory options.
+func newTestActor(store map[string][]byte, options ...func(*BankAccountActor)) *BankAccountActor {
+	a := &BankAccountActor{}
+	for _, option := range options {
+		option(a)
+	}
+	a.SetID("account-test")
+	a.SetStateManager(newMemoryStateManager(store))
+	return a
+}
+
+// saveTestState saves the pending state changes of an actor, as Dapr does after a successful call.
+func saveTestState(t *testing.T, a *BankAccountActor) {
+	t.Helper()
+	if err := a.GetStateManager().Save(context.Background()); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+}
+
+// appendTestEvents creates an account and deposits into it until the log holds n events, saving
+// the state after every call.
+func appendTestEvents(t *testing.T, store map[string][]byte, n int, options ...func(*BankAccountActor)) {
+	t.Helper()
+	ctx := context.Background()
+	a := newTestActor(store, options...)
+	if _, err := a.CreateAccount(ctx, CreateAccountRequest{OwnerName: "Jane Doe", InitialDeposit: 100}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	saveTestState(t, a)
+	for i := 1; i < n; i++ {
+		if _, err := a.Deposit(ctx, DepositRequest{Amount: float64(i), Description: "deposit"}); err != nil {
+			t.Fatalf("Deposit %d: %v", i, err)
+		}
+		saveTestState(t, a)
+	}
+}
+
+func TestAppendEventWritesPerEventKeys(t *testing.T) {
+	store := make(map[string][]byte)
+	appendTestEvents(t, store, 5)
+
+	for _, key := range []string{"events/000001", "events/000002", "events/000003", "events/000004", "events/000005"} {
+		if _, ok := store[key]; !ok {
+			t.Errorf("event key %s not written", key)
+		}
+	}
+	if _, ok := store["events/000006"]; ok {
+		t.Error("event key events/000006 written past the head")
+	}
+	if _, ok := store[legacyEventsKey]; ok {
+		t.Errorf("legacy key %s written", legacyEventsKey)
+	}
+
+	var head eventLogHead
+	if err := json.Unmarshal(store[headKey], &head); err != nil {
+		t.Fatalf("failed to decode head: %v", err)
+	}
+	if head.Sequence != 5 {
+		t.Errorf("head = %d, want 5", head.Sequence)
+	}
+
+	var event StoredEvent
+	if err := json.Unmarshal(store["events/000005"], &event); err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+	if data, ok := event.Data.(MoneyDepositedEventData); !ok || data.Amount != 4 {
+		t.Errorf("events/000005 = %+v, want the fourth deposit", event.Data)
+	}
+}
+
+func TestAppendEventMovesHeadPerCall(t *testing.T) {
+	store := make(map[string][]byte)
+	appendTestEvents(t, store, 1)
+
+	// A newly activated instance continues the log after the stored head
+	a := newTestActor(store)
+	state, err := a.Deposit(context.Background(), DepositRequest{Amount: 10, Description: "deposit"})
+	if err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	saveTestState(t, a)
+
+	if state.Version != 2 || state.Balance != 110 {
+		t.Errorf("state = version %d balance %v, want version 2 balance 110", state.Version, state.Balance)
+	}
+	var head eventLogHead
+	if err := json.Unmarshal(store[headKey], &head); err != nil || head.Sequence != 2 {
+		t.Errorf("head = %s, want sequence 2", store[headKey])
+	}
+}
+
+func TestCheckExpectedVersion(t *testing.T) {
+	version := func(v int64) *int64 { return &v }
+	tests := []struct {
+		name     string
+		expected *int64
+		conflict bool
+	}{
+		{name: "no expected version", expected: nil},
+		{name: "match", expected: version(3)},
+		{name: "behind", expected: version(2), conflict: true},
+		{name: "ahead", expected: version(4), conflict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &BankAccountActor{headSequence: 3}
+			err := a.checkExpectedVersion(tt.expected)
+			if !tt.conflict {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var conflict *VersionConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("error = %v, want a *VersionConflictError", err)
+			}
+			if conflict.CurrentVersion != 3 || conflict.ExpectedVersion != *tt.expected {
+				t.Errorf("conflict = current %d expected %d, want current 3 expected %d",
+					conflict.CurrentVersion, conflict.ExpectedVersion, *tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpectedVersionOfCommands(t *testing.T) {
+	ctx := context.Background()
+	version := func(v int64) *int64 { return &v }
+
+	// A new account is at version 0
+	a := newTestActor(make(map[string][]byte))
+	var conflict *VersionConflictError
+	if _, err := a.CreateAccount(ctx, CreateAccountRequest{OwnerName: "Jane Doe", InitialDeposit: 100, ExpectedVersion: version(1)}); !errors.As(err, &conflict) {
+		t.Fatalf("CreateAccount at version 1: error = %v, want a *VersionConflictError", err)
+	}
+	state, err := a.CreateAccount(ctx, CreateAccountRequest{OwnerName: "Jane Doe", InitialDeposit: 100, ExpectedVersion: version(0)})
+	if err != nil {
+		t.Fatalf("CreateAccount at version 0: %v", err)
+	}
+	if state.Version != 1 {
+		t.Errorf("version = %d, want 1", state.Version)
+	}
+
+	// A command based on a stale read is rejected without an event
+	if _, err := a.Deposit(ctx, DepositRequest{Amount: 10, Description: "deposit", ExpectedVersion: version(0)}); !errors.As(err, &conflict) {
+		t.Fatalf("Deposit at a stale version: error = %v, want a *VersionConflictError", err)
+	}
+	if conflict.CurrentVersion != 1 || conflict.ExpectedVersion != 0 {
+		t.Errorf("conflict = current %d expected %d, want current 1 expected 0", conflict.CurrentVersion, conflict.ExpectedVersion)
+	}
+	if state, err := a.Withdraw(ctx, WithdrawRequest{Amount: 10, Description: "withdrawal", ExpectedVersion: version(1)}); err != nil || state.Version != 2 {
+		t.Errorf("Withdraw at the current version = %+v, %v, want version 2", state, err)
+	}
+}
+
+// loadTestState activates a new instance on store and returns its state and the sequence number of
+// the snapshot it loaded.
+func loadTestState(t *testing.T, store map[string][]byte) (*BankAccountState, int64) {
+	t.Helper()
+	a := newTestActor(store)
+	state, err := a.GetBalance(context.Background())
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	return state, a.snapshotSequence
+}
+
+// testSnapshot decodes the snapshot in store, or returns nil when there is none.
+func testSnapshot(t *testing.T, store map[string][]byte) *accountSnapshot {
+	t.Helper()
+	data, ok := store[snapshotKey]
+	if !ok {
+		return nil
+	}
+	var snapshot accountSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	return &snapshot
+}
+
+func TestSnapshotThresholds(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   SnapshotPolicy
+		events   int
+		sequence int64 // Sequence number of the snapshot; 0 for none
+	}{
+		{name: "event count not reached", policy: SnapshotPolicy{EveryEvents: 3}, events: 2},
+		{name: "event count reached", policy: SnapshotPolicy{EveryEvents: 3}, events: 3, sequence: 3},
+		{name: "event count crossed", policy: SnapshotPolicy{EveryEvents: 3}, events: 5, sequence: 3},
+		{name: "bytes not reached", policy: SnapshotPolicy{EveryBytes: 1 << 20}, events: 5},
+		{name: "bytes crossed", policy: SnapshotPolicy{EveryBytes: 1}, events: 1, sequence: 1},
+		{name: "either threshold", policy: SnapshotPolicy{EveryEvents: 4, EveryBytes: 1 << 20}, events: 4, sequence: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := make(map[string][]byte)
+			appendTestEvents(t, store, tt.events, WithSnapshots(tt.policy))
+
+			snapshot := testSnapshot(t, store)
+			if tt.sequence == 0 {
+				if snapshot != nil {
+					t.Fatalf("snapshot taken at event %d", snapshot.Sequence)
+				}
+				return
+			}
+			if snapshot == nil {
+				t.Fatal("no snapshot taken")
+			}
+			if snapshot.Sequence != tt.sequence || snapshot.Version != snapshotVersion {
+				t.Errorf("snapshot = sequence %d version %d, want sequence %d version %d",
+					snapshot.Sequence, snapshot.Version, tt.sequence, snapshotVersion)
+			}
+		})
+	}
+}
+
+func TestSnapshotBytesThreshold(t *testing.T) {
+	// A threshold halfway through the second event. Sizes vary by a few bytes between runs, as
+	// timestamps are encoded without trailing zeros.
+	measured := make(map[string][]byte)
+	appendTestEvents(t, measured, 2)
+	size := len(measured[eventKey(1)]) + len(measured[eventKey(2)])/2
+
+	store := make(map[string][]byte)
+	appendTestEvents(t, store, 3, WithSnapshots(SnapshotPolicy{EveryBytes: size}))
+	if snapshot := testSnapshot(t, store); snapshot == nil || snapshot.Sequence != 2 {
+		t.Errorf("snapshot = %+v, want one at event 2", snapshot)
+	}
+}
+
+func TestSnapshotAndTailEqualFullReplay(t *testing.T) {
+	store := make(map[string][]byte)
+	appendTestEvents(t, store, 5, WithSnapshots(SnapshotPolicy{EveryEvents: 3}))
+
+	fromSnapshot, snapshotSequence := loadTestState(t, store)
+	if snapshotSequence != 3 {
+		t.Fatalf("loaded snapshot at event %d, want 3", snapshotSequence)
+	}
+
+	delete(store, snapshotKey)
+	replayed, snapshotSequence := loadTestState(t, store)
+	if snapshotSequence != 0 {
+		t.Fatalf("loaded snapshot at event %d after removing it", snapshotSequence)
+	}
+
+	if !reflect.DeepEqual(fromSnapshot, replayed) {
+		t.Errorf("state from snapshot = %+v, full replay = %+v", fromSnapshot, replayed)
+	}
+	if replayed.Version != 5 || replayed.Balance != 110 {
+		t.Errorf("state = version %d balance %v, want version 5 balance 110", replayed.Version, replayed.Balance)
+	}
+}
+
+func TestSnapshotOfOtherVersionIsReplayed(t *testing.T) {
+	store := make(map[string][]byte)
+	appendTestEvents(t, store, 5, WithSnapshots(SnapshotPolicy{EveryEvents: 3}))
+	want, _ := loadTestState(t, store)
+
+	// A snapshot of another format, whose state would be wrong if it were used
+	snapshot := testSnapshot(t, store)
+	snapshot.Version = snapshotVersion + 1
+	snapshot.State = json.RawMessage(`{"accountId":"account-test","balance":-1}`)
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store[snapshotKey] = data
+
+	got, snapshotSequence := loadTestState(t, store)
+	if snapshotSequence != 0 {
+		t.Errorf("loaded snapshot at event %d, want a full replay", snapshotSequence)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("state = %+v, want %+v", got, want)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	key := func(k string) *string { return &k }
+
+	tests := []struct {
+		name     string
+		retry    func(ctx context.Context, a *BankAccountActor) (*BankAccountState, error)
+		conflict bool
+	}{
+		{
+			name: "same request",
+			retry: func(ctx context.Context, a *BankAccountActor) (*BankAccountState, error) {
+				return a.Deposit(ctx, DepositRequest{Amount: 25, Description: "deposit", IdempotencyKey: key("key-1")})
+			},
+		},
+		{
+			name: "different amount",
+			retry: func(ctx context.Context, a *BankAccountActor) (*BankAccountState, error) {
+				return a.Deposit(ctx, DepositRequest{Amount: 30, Description: "deposit", IdempotencyKey: key("key-1")})
+			},
+			conflict: true,
+		},
+		{
+			name: "different method",
+			retry: func(ctx context.Context, a *BankAccountActor) (*BankAccountState, error) {
+				return a.Withdraw(ctx, WithdrawRequest{Amount: 25, Description: "deposit", IdempotencyKey: key("key-1")})
+			},
+			conflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := make(map[string][]byte)
+			appendTestEvents(t, store, 1)
+
+			a := newTestActor(store)
+			original, err := a.Deposit(ctx, DepositRequest{Amount: 25, Description: "deposit", IdempotencyKey: key("key-1")})
+			if err != nil {
+				t.Fatalf("Deposit: %v", err)
+			}
+			saveTestState(t, a)
+
+			// The retry runs on a newly activated instance, which reads the window from the store
+			result, err := tt.retry(ctx, newTestActor(store))
+			if tt.conflict {
+				var conflict *IdempotencyKeyConflictError
+				if !errors.As(err, &conflict) || conflict.IdempotencyKey != "key-1" {
+					t.Fatalf("error = %v, want *IdempotencyKeyConflictError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("retry: %v", err)
+			}
+			if !reflect.DeepEqual(result, original) {
+				t.Errorf("retry = %+v, want the original result %+v", result, original)
+			}
+		})
+	}
+}
+
+// unknownEventData is event data that applyEvent does not handle.
+type unknownEventData struct{}
+
+func (unknownEventData) Validate() error     { return nil }
+func (unknownEventData) isAccountEventData() {}
+
+func TestAppendEventNotApplied(t *testing.T) {
+	ctx := context.Background()
+	store := make(map[string][]byte)
+	appendTestEvents(t, store, 1)
+
+	a := newTestActor(store)
+	if _, err := a.GetBalance(ctx); err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if err := a.appendEvent(ctx, AccountEventTypeMoneyDeposited, unknownEventData{}, nil); err == nil {
+		t.Fatal("appendEvent applied unknown event data")
+	}
+
+	// Nothing is staged, so the next call continues from the unchanged account and saves only its own event
+	if ok, _ := a.GetStateManager().Contains(ctx, eventKey(2)); ok {
+		t.Error("event stored although it could not be applied")
+	}
+	state, err := a.Withdraw(ctx, WithdrawRequest{Amount: 40, Description: "withdrawal"})
+	if err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+	saveTestState(t, a)
+	if state.Version != 2 || state.Balance != 60 {
+		t.Errorf("state = version %d balance %v, want version 2 balance 60", state.Version, state.Balance)
+	}
+	if _, ok := store[eventKey(3)]; ok {
+		t.Error("event stored after the head")
+	}
+	if state, _ := loadTestState(t, store); state.Version != 2 || state.Balance != 60 {
+		t.Errorf("replayed state = version %d balance %v, want version 2 balance 60", state.Version, state.Balance)
+	}
+}
+
+func TestMigrateLegacyEvents(t *testing.T) {
+	ctx := context.Background()
+
+	// An account stored as one array, before events had their own keys
+	store := map[string][]byte{legacyEventsKey: []byte(`[` +
+		`{"eventId":"00000000-0000-4000-8000-000000000001","eventType":"AccountCreated","timestamp":"2024-01-01T00:00:00Z",` +
+		`"data":{"ownerName":"Jane Doe","initialDeposit":100,"createdAt":"2024-01-01T00:00:00Z"}},` +
+		`{"eventId":"00000000-0000-4000-8000-000000000002","eventType":"MoneyDeposited","timestamp":"2024-01-02T00:00:00Z",` +
+		`"data":{"amount":10,"description":"deposit","timestamp":"2024-01-02T00:00:00Z"}},` +
+		`{"eventId":"00000000-0000-4000-8000-000000000003","eventType":"MoneyWithdrawn","timestamp":"2024-01-03T00:00:00Z",` +
+		`"data":{"amount":30,"description":"withdrawal","timestamp":"2024-01-03T00:00:00Z"}}]`)}
+
+	a := newTestActor(store)
+	state, err := a.GetBalance(ctx)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if state.Version != 3 || state.Balance != 80 {
+		t.Errorf("state = version %d balance %v, want version 3 balance 80", state.Version, state.Balance)
+	}
+	saveTestState(t, a)
+
+	// The array is replaced by per-event keys, in order, and a head
+	if _, ok := store[legacyEventsKey]; ok {
+		t.Errorf("legacy key %s not removed", legacyEventsKey)
+	}
+	for i, eventType := range []AccountEventType{AccountEventTypeAccountCreated, AccountEventTypeMoneyDeposited, AccountEventTypeMoneyWithdrawn} {
+		key := eventKey(int64(i + 1))
+		var event StoredEvent
+		if err := json.Unmarshal(store[key], &event); err != nil {
+			t.Fatalf("failed to decode %s: %v", key, err)
+		}
+		if id := fmt.Sprintf("00000000-0000-4000-8000-%012d", i+1); event.EventType != eventType || event.EventID.String() != id {
+			t.Errorf("%s = %s %s, want %s %s", key, event.EventType, event.EventID, eventType, id)
+		}
+	}
+	var head eventLogHead
+	if err := json.Unmarshal(store[headKey], &head); err != nil || head.Sequence != 3 {
+		t.Errorf("head = %s, want sequence 3", store[headKey])
+	}
+
+	// A newly activated instance replays the migrated log
+	if state, _ := loadTestState(t, store); state.Version != 3 || state.Balance != 80 {
+		t.Errorf("replayed state = version %d balance %v, want version 3 balance 80", state.Version, state.Balance)
+	}
+}
+
+// storeTestEvent stores the raw JSON of an event as the next event of the log in store.
+func storeTestEvent(t *testing.T, store map[string][]byte, event string) {
+	t.Helper()
+	var head eventLogHead
+	if err := json.Unmarshal(store[headKey], &head); err != nil {
+		t.Fatalf("failed to decode head: %v", err)
+	}
+	head.Sequence++
+	data, err := json.Marshal(head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store[eventKey(head.Sequence)] = []byte(event)
+	store[headKey] = data
+}
+
+func TestReplayNewerSchemaVersion(t *testing.T) {
+	ctx := context.Background()
+	store := make(map[string][]byte)
+	appendTestEvents(t, store, 1)
+	storeTestEvent(t, store, `{"eventId":"6f1c2b1e-0a4d-4f5e-9c3b-2d7e8f9a0b1c","eventType":"MoneyDeposited","schemaVersion":99,`+
+		`"timestamp":"2024-01-01T00:00:00Z","data":{"amount":10,"description":"deposit","timestamp":"2024-01-01T00:00:00Z"}}`)
+
+	a := newTestActor(store)
+	for i := 0; i < 2; i++ {
+		_, err := a.Withdraw(ctx, WithdrawRequest{Amount: 1, Description: "withdrawal"})
+		if err == nil || !strings.Contains(err.Error(), "schema version 99, newer than the supported version 1") {
+			t.Fatalf("call %d: error = %v, want the unsupported schema version", i+1, err)
+		}
+	}
+	if ok, _ := a.GetStateManager().Contains(ctx, eventKey(3)); ok {
+		t.Error("event appended to a log that could not be replayed")
+	}
+}
+
+// registerTestUpcaster makes MoneyDeposited events schema version 2, in which the "note" of
+// version 1 is renamed to "description", for the duration of the test.
+func registerTestUpcaster(t *testing.T) {
+	versions, upcasters := eventSchemaVersions, eventUpcasters
+	t.Cleanup(func() {
+		eventSchemaVersions, eventUpcasters = versions, upcasters
+	})
+
+	eventSchemaVersions = make(map[AccountEventType]int)
+	for eventType, version := range versions {
+		eventSchemaVersions[eventType] = version
+	}
+	eventSchemaVersions[AccountEventTypeMoneyDeposited] = 2
+
+	eventUpcasters = map[upcasterKey]upcaster{
+		{AccountEventTypeMoneyDeposited, 1}: func(data json.RawMessage) (json.RawMessage, error) {
+			var payload map[string]interface{}
+			if err := json.Unmarshal(data, &payload); err != nil {
+				return nil, err
+			}
+			payload["description"] = payload["note"]
+			delete(payload, "note")
+			return json.Marshal(payload)
+		},
+	}
+}
+
+func TestReplayUpcastsLegacyEvents(t *testing.T) {
+	registerTestUpcaster(t)
+	ctx := context.Background()
+	store := make(map[string][]byte)
+	appendTestEvents(t, store, 1)
+
+	// A version 1 event stored before events recorded their schema version
+	storeTestEvent(t, store, `{"eventId":"6f1c2b1e-0a4d-4f5e-9c3b-2d7e8f9a0b1c","eventType":"MoneyDeposited",`+
+		`"timestamp":"2024-01-01T00:00:00Z","data":{"amount":10,"note":"legacy deposit","timestamp":"2024-01-01T00:00:00Z"}}`)
+
+	a := newTestActor(store)
+	state, err := a.GetBalance(ctx)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if state.Version != 2 || state.Balance != 110 {
+		t.Errorf("state = version %d balance %v, want version 2 balance 110", state.Version, state.Balance)
+	}
+
+	events, err := a.getAllEvents(ctx)
+	if err != nil {
+		t.Fatalf("getAllEvents: %v", err)
+	}
+	deposit, ok := events[1].Data.(MoneyDepositedEventData)
+	if !ok || deposit.Description != "legacy deposit" {
+		t.Errorf("upcast event = %+v, want the note as description", events[1].Data)
+	}
+	if events[1].SchemaVersion != 2 {
+		t.Errorf("upcast event has schema version %d, want 2", events[1].SchemaVersion)
+	}
+
+	// New events are stored with the current version
+	if _, err := a.Deposit(ctx, DepositRequest{Amount: 5, Description: "deposit"}); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	saveTestState(t, a)
+	var stored struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(store[eventKey(3)], &stored); err != nil || stored.SchemaVersion != 2 {
+		t.Errorf("new event = %s, want schema version 2", store[eventKey(3)])
+	}
+}
+
+func TestReplayNewerSchemaVersionWithUpcaster(t *testing.T) {
+	registerTestUpcaster(t)
+	store := make(map[string][]byte)
+	appendTestEvents(t, store, 1)
+	storeTestEvent(t, store, `{"eventId":"6f1c2b1e-0a4d-4f5e-9c3b-2d7e8f9a0b1c","eventType":"MoneyDeposited","schemaVersion":3,`+
+		`"timestamp":"2024-01-01T00:00:00Z","data":{"amount":10,"description":"deposit","timestamp":"2024-01-01T00:00:00Z"}}`)
+
+	_, err := newTestActor(store).GetBalance(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "MoneyDeposited event has schema version 3, newer than the supported version 2") {
+		t.Fatalf("error = %v, want the unsupported schema version", err)
+	}
+}
@@ -0,0 +1,281 @@
+// Package bankaccountactor provides primitives for OpenAPI-based schema validation.
+//
+// Code generated from OpenAPI specification. DO NOT EDIT manually.
+package bankaccountactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dapr/go-sdk/actor"
+)
+
+// contractActorID is the actor ID the contract test invokes, derived from the actor type.
+const contractActorID = "bank-account-actor-contract"
+
+// TestBankAccountActorContract invokes every method of the actor, in method name order, with the request
+// built from the OpenAPI examples. Each call runs on a newly activated instance that shares the state
+// saved by the previous calls, as after a Dapr actor deactivation. A result must decode into the
+// declared response type and pass its validation; an error must be one of the errors declared for
+// the method.
+func TestBankAccountActorContract(t *testing.T) {
+	store := make(map[string][]byte)
+
+	t.Run("CreateAccount", func(t *testing.T) {
+		ctx := context.Background()
+		server := newContractServer(t, store)
+
+		var request CreateAccountRequest
+		decodeContractJSON(t, `{"initialDeposit":100,"ownerName":"John Doe"}`, &request)
+		result, err := server.CreateAccount(ctx, request)
+		if err != nil {
+			t.Fatalf("CreateAccount returned an error not declared in the schema: %v", err)
+		}
+		data := saveContractResult(t, server, result)
+		if envelope := decodeContractError(t, data); envelope != nil {
+			checkContractError(t, envelope, errorStatusesCreateAccount)
+			return
+		}
+
+		var response BankAccountState
+		decodeContractResult(t, data, &response)
+	})
+
+	t.Run("Deposit", func(t *testing.T) {
+		ctx := context.Background()
+		server := newContractServer(t, store)
+
+		var request DepositRequest
+		decodeContractJSON(t, `{"amount":250,"description":"Salary deposit","idempotencyKey":"deposit-7f3a9c"}`, &request)
+		result, err := server.Deposit(ctx, request)
+		if err != nil {
+			t.Fatalf("Deposit returned an error not declared in the schema: %v", err)
+		}
+		data := saveContractResult(t, server, result)
+		if envelope := decodeContractError(t, data); envelope != nil {
+			checkContractError(t, envelope, errorStatusesDeposit)
+			return
+		}
+
+		var response BankAccountState
+		decodeContractResult(t, data, &response)
+	})
+
+	t.Run("GetBalance", func(t *testing.T) {
+		ctx := context.Background()
+		server := newContractServer(t, store)
+
+		result, err := server.GetBalance(ctx)
+		if err != nil {
+			t.Fatalf("GetBalance returned an error not declared in the schema: %v", err)
+		}
+		data := saveContractResult(t, server, result)
+		if envelope := decodeContractError(t, data); envelope != nil {
+			checkContractError(t, envelope, errorStatusesGetBalance)
+			return
+		}
+
+		var response BankAccountState
+		decodeContractResult(t, data, &response)
+	})
+
+	t.Run("GetHistory", func(t *testing.T) {
+		ctx := context.Background()
+		server := newContractServer(t, store)
+
+		result, err := server.GetHistory(ctx)
+		if err != nil {
+			t.Fatalf("GetHistory returned an error not declared in the schema: %v", err)
+		}
+		data := saveContractResult(t, server, result)
+		if envelope := decodeContractError(t, data); envelope != nil {
+			checkContractError(t, envelope, errorStatusesGetHistory)
+			return
+		}
+
+		var response TransactionHistory
+		decodeContractResult(t, data, &response)
+	})
+
+	t.Run("Withdraw", func(t *testing.T) {
+		ctx := context.Background()
+		server := newContractServer(t, store)
+
+		var request WithdrawRequest
+		decodeContractJSON(t, `{"amount":50,"description":"ATM withdrawal","idempotencyKey":"withdrawal-2b81d4"}`, &request)
+		result, err := server.Withdraw(ctx, request)
+		if err != nil {
+			t.Fatalf("Withdraw returned an error not declared in the schema: %v", err)
+		}
+		data := saveContractResult(t, server, result)
+		if envelope := decodeContractError(t, data); envelope != nil {
+			checkContractError(t, envelope, errorStatusesWithdraw)
+			return
+		}
+
+		var response BankAccountState
+		decodeContractResult(t, data, &response)
+	})
+
+}
+
+// newContractServer activates an actor instance through NewActorFactory with its state in store.
+func newContractServer(t *testing.T, store map[string][]byte) *BankAccountActorServer {
+	t.Helper()
+	server, ok := NewActorFactory()().(*BankAccountActorServer)
+	if !ok {
+		t.Fatal("NewActorFactory does not return a *BankAccountActorServer")
+	}
+	server.SetID(contractActorID)
+	server.SetStateManager(newMemoryStateManager(store))
+	return server
+}
+
+// saveContractResult encodes a method result as Dapr returns it to the caller and then saves the
+// actor state, as Dapr does after every successful call.
+func saveContractResult(t *testing.T, server *BankAccountActorServer, result interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	if err := server.SaveState(context.Background()); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+	return data
+}
+
+// decodeContractJSON decodes data, rejecting properties the schema does not declare.
+func decodeContractJSON(t *testing.T, data string, v interface{}) {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		t.Fatalf("%s does not match the schema: %v", data, err)
+	}
+}
+
+// decodeContractResult decodes a result into its response type and validates it against the schema.
+func decodeContractResult(t *testing.T, data []byte, v interface{}) {
+	t.Helper()
+	if bytes.Equal(data, []byte("null")) {
+		t.Fatal("method returned no result")
+	}
+	decodeContractJSON(t, string(data), v)
+	if validator, ok := v.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			t.Fatalf("result %s is invalid: %v", data, err)
+		}
+	}
+}
+
+// decodeContractError returns the error envelope of a result, or nil for a successful result.
+func decodeContractError(t *testing.T, data []byte) *ErrorEnvelope {
+	t.Helper()
+	var result struct {
+		ActorError *ErrorEnvelope `json:"actorError"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil // Not an object, so not an envelope
+	}
+	return result.ActorError
+}
+
+// checkContractError checks that an envelope carries one of the errors declared for the method,
+// under its declared status and with details matching its schema.
+func checkContractError(t *testing.T, envelope *ErrorEnvelope, statuses map[string]int) {
+	t.Helper()
+	status, ok := statuses[envelope.Code]
+	if !ok {
+		t.Fatalf("error %q is not declared for the method", envelope.Code)
+	}
+	if envelope.Status != status {
+		t.Fatalf("error %q has status %d, declared %d", envelope.Code, envelope.Status, status)
+	}
+	validator, ok := decodeActorError(envelope).(interface{ Validate() error })
+	if !ok {
+		t.Fatalf("details of error %q do not match its schema: %s", envelope.Code, envelope.Details)
+	}
+	if err := validator.Validate(); err != nil {
+		t.Fatalf("details of error %q are invalid: %v", envelope.Code, err)
+	}
+}
+
+// memoryStateManager is an in-memory actor.StateManagerContext. Values are stored JSON-encoded, as
+// in a Dapr state store, and changes become visible to other instances when they are saved.
+type memoryStateManager struct {
+	store   map[string][]byte
+	changes map[string][]byte // Pending changes; nil marks a removed key
+}
+
+var _ actor.StateManagerContext = (*memoryStateManager)(nil)
+
+func newMemoryStateManager(store map[string][]byte) *memoryStateManager {
+	return &memoryStateManager{store: store, changes: make(map[string][]byte)}
+}
+
+func (m *memoryStateManager) lookup(stateName string) ([]byte, bool) {
+	if value, ok := m.changes[stateName]; ok {
+		return value, value != nil
+	}
+	value, ok := m.store[stateName]
+	return value, ok
+}
+
+func (m *memoryStateManager) Add(ctx context.Context, stateName string, value any) error {
+	if _, ok := m.lookup(stateName); ok {
+		return fmt.Errorf("duplicate state: %s", stateName)
+	}
+	return m.Set(ctx, stateName, value)
+}
+
+func (m *memoryStateManager) Get(_ context.Context, stateName string, reply any) error {
+	value, ok := m.lookup(stateName)
+	if !ok {
+		return fmt.Errorf("state not found: %s", stateName)
+	}
+	return json.Unmarshal(value, reply)
+}
+
+func (m *memoryStateManager) Set(_ context.Context, stateName string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode state %s: %v", stateName, err)
+	}
+	m.changes[stateName] = data
+	return nil
+}
+
+func (m *memoryStateManager) SetWithTTL(ctx context.Context, stateName string, value any, _ time.Duration) error {
+	return m.Set(ctx, stateName, value)
+}
+
+func (m *memoryStateManager) Remove(_ context.Context, stateName string) error {
+	m.changes[stateName] = nil
+	return nil
+}
+
+func (m *memoryStateManager) Contains(_ context.Context, stateName string) (bool, error) {
+	_, ok := m.lookup(stateName)
+	return ok, nil
+}
+
+func (m *memoryStateManager) Save(ctx context.Context) error {
+	for stateName, value := range m.changes {
+		if value == nil {
+			delete(m.store, stateName)
+		} else {
+			m.store[stateName] = value
+		}
+	}
+	m.Flush(ctx)
+	return nil
+}
+
+func (m *memoryStateManager) Flush(context.Context) {
+	m.changes = make(map[string][]byte)
+}
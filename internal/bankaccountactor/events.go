@@ -0,0 +1,75 @@
+package bankaccountactor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Stored events carry the schema version of their payload. When the payload of an event type
+// changes, its version in eventSchemaVersions is incremented and an upcaster from the previous
+// version is added to eventUpcasters. Events are upcast to the current version as they are read,
+// so replay only ever sees payloads in the shape of the generated event data types.
+
+// eventSchemaVersions is the current payload schema version of each event type. Reading an event
+// of a type missing here fails instead of being skipped during replay.
+var eventSchemaVersions = map[AccountEventType]int{
+	AccountEventTypeAccountCreated: 1,
+	AccountEventTypeMoneyDeposited: 1,
+	AccountEventTypeMoneyWithdrawn: 1,
+}
+
+// legacySchemaVersion is the schema version of events stored before events recorded one.
+const legacySchemaVersion = 1
+
+// upcaster converts an event payload from one schema version to the next.
+type upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+type upcasterKey struct {
+	EventType   AccountEventType
+	FromVersion int
+}
+
+// eventUpcasters holds the upcaster from each schema version of an event type to the following
+// version. For example, after MoneyDepositedEventData gains a required currency in version 2:
+//
+//	{AccountEventTypeMoneyDeposited, 1}: func(data json.RawMessage) (json.RawMessage, error) {
+//		var payload map[string]interface{}
+//		if err := json.Unmarshal(data, &payload); err != nil {
+//			return nil, err
+//		}
+//		payload["currency"] = "USD"
+//		return json.Marshal(payload)
+//	},
+var eventUpcasters = map[upcasterKey]upcaster{}
+
+// currentSchemaVersion returns the schema version events of eventType are stored with.
+func currentSchemaVersion(eventType AccountEventType) (int, error) {
+	version, ok := eventSchemaVersions[eventType]
+	if !ok {
+		return 0, fmt.Errorf("unknown event type %q", eventType)
+	}
+	return version, nil
+}
+
+// upcastEvent converts the payload of an event stored with schemaVersion to the current schema
+// version of eventType, applying the upcaster of each version in between.
+func upcastEvent(eventType AccountEventType, schemaVersion int, data json.RawMessage) (json.RawMessage, error) {
+	current, err := currentSchemaVersion(eventType)
+	if err != nil {
+		return nil, err
+	}
+	if schemaVersion > current {
+		return nil, fmt.Errorf("%s event has schema version %d, newer than the supported version %d", eventType, schemaVersion, current)
+	}
+
+	for version := schemaVersion; version < current; version++ {
+		upcast, ok := eventUpcasters[upcasterKey{EventType: eventType, FromVersion: version}]
+		if !ok {
+			return nil, fmt.Errorf("no upcaster for %s events from schema version %d", eventType, version)
+		}
+		if data, err = upcast(data); err != nil {
+			return nil, fmt.Errorf("failed to upcast %s event from schema version %d: %v", eventType, version, err)
+		}
+	}
+	return data, nil
+}
@@ -4,26 +4,139 @@
 package bankaccountactor
 
 import (
+	"context"
 	"fmt"
 	"github.com/dapr/go-sdk/actor"
 )
 
 // NewActorFactory creates a factory function for BankAccountActor with a cleaner API.
 // Returns a factory function compatible with Dapr's RegisterActorImplFactoryContext.
+// Options configure each new BankAccountActor instance before it is wrapped.
 // Usage: s.RegisterActorImplFactoryContext(bankaccountactor.NewActorFactory())
-func NewActorFactory() func() actor.ServerContext {
+func NewActorFactory(options ...func(*BankAccountActor)) func() actor.ServerContext {
 	return func() actor.ServerContext {
 		// Create a new BankAccountActor instance
 		impl := &BankAccountActor{}
-		
+		for _, option := range options {
+			option(impl)
+		}
+
 		// Compile-time check ensures the implementation satisfies the schema
 		var _ BankAccountActorAPI = impl
-		
+
 		// Verify the actor type matches the schema
 		if impl.Type() != ActorTypeBankAccountActor {
 			panic(fmt.Sprintf("actor implementation Type() returns '%s', expected '%s'", impl.Type(), ActorTypeBankAccountActor))
 		}
-		
-		return impl
+
+		// Wrap the implementation so invalid input is rejected before it runs
+		return &BankAccountActorServer{BankAccountActorAPI: impl}
+	}
+}
+
+// BankAccountActorServer wraps a BankAccountActorAPI implementation and validates the actor ID
+// and request bodies against the OpenAPI schema before delegating to the implementation.
+// Methods that declare error responses return an ActorResult, so those errors reach the client as an ErrorEnvelope.
+type BankAccountActorServer struct {
+	BankAccountActorAPI
+}
+
+// errorStatusesCreateAccount maps the codes of the errors declared for CreateAccount to their HTTP status.
+var errorStatusesCreateAccount = map[string]int{
+	ErrorCodeAccountAlreadyExists: 409,
+	ErrorCodeVersionConflict:      412,
+}
+
+// CreateAccount validates the input, delegates to the implementation and returns declared errors in an ErrorEnvelope.
+func (s *BankAccountActorServer) CreateAccount(ctx context.Context, request CreateAccountRequest) (*ActorResult, error) {
+	if err := ValidateActorID(s.ID()); err != nil {
+		return nil, err
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	result, err := s.BankAccountActorAPI.CreateAccount(ctx, request)
+	if err != nil {
+		return encodeActorError(err, errorStatusesCreateAccount)
+	}
+	return &ActorResult{Value: result}, nil
+}
+
+// errorStatusesDeposit maps the codes of the errors declared for Deposit to their HTTP status.
+var errorStatusesDeposit = map[string]int{
+	ErrorCodeAccountNotFound:        404,
+	ErrorCodeVersionConflict:        412,
+	ErrorCodeIdempotencyKeyConflict: 422,
+}
+
+// Deposit validates the input, delegates to the implementation and returns declared errors in an ErrorEnvelope.
+func (s *BankAccountActorServer) Deposit(ctx context.Context, request DepositRequest) (*ActorResult, error) {
+	if err := ValidateActorID(s.ID()); err != nil {
+		return nil, err
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	result, err := s.BankAccountActorAPI.Deposit(ctx, request)
+	if err != nil {
+		return encodeActorError(err, errorStatusesDeposit)
+	}
+	return &ActorResult{Value: result}, nil
+}
+
+// errorStatusesGetBalance maps the codes of the errors declared for GetBalance to their HTTP status.
+var errorStatusesGetBalance = map[string]int{
+	ErrorCodeAccountNotFound: 404,
+}
+
+// GetBalance validates the input, delegates to the implementation and returns declared errors in an ErrorEnvelope.
+func (s *BankAccountActorServer) GetBalance(ctx context.Context) (*ActorResult, error) {
+	if err := ValidateActorID(s.ID()); err != nil {
+		return nil, err
+	}
+	result, err := s.BankAccountActorAPI.GetBalance(ctx)
+	if err != nil {
+		return encodeActorError(err, errorStatusesGetBalance)
+	}
+	return &ActorResult{Value: result}, nil
+}
+
+// errorStatusesGetHistory maps the codes of the errors declared for GetHistory to their HTTP status.
+var errorStatusesGetHistory = map[string]int{
+	ErrorCodeAccountNotFound: 404,
+}
+
+// GetHistory validates the input, delegates to the implementation and returns declared errors in an ErrorEnvelope.
+func (s *BankAccountActorServer) GetHistory(ctx context.Context) (*ActorResult, error) {
+	if err := ValidateActorID(s.ID()); err != nil {
+		return nil, err
+	}
+	result, err := s.BankAccountActorAPI.GetHistory(ctx)
+	if err != nil {
+		return encodeActorError(err, errorStatusesGetHistory)
+	}
+	return &ActorResult{Value: result}, nil
+}
+
+// errorStatusesWithdraw maps the codes of the errors declared for Withdraw to their HTTP status.
+var errorStatusesWithdraw = map[string]int{
+	ErrorCodeInsufficientFunds:      400,
+	ErrorCodeAccountNotFound:        404,
+	ErrorCodeVersionConflict:        412,
+	ErrorCodeIdempotencyKeyConflict: 422,
+}
+
+// Withdraw validates the input, delegates to the implementation and returns declared errors in an ErrorEnvelope.
+func (s *BankAccountActorServer) Withdraw(ctx context.Context, request WithdrawRequest) (*ActorResult, error) {
+	if err := ValidateActorID(s.ID()); err != nil {
+		return nil, err
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	result, err := s.BankAccountActorAPI.Withdraw(ctx, request)
+	if err != nil {
+		return encodeActorError(err, errorStatusesWithdraw)
 	}
-}
\ No newline at end of file
+	return &ActorResult{Value: result}, nil
+}
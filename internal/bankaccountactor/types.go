@@ -3,6 +3,251 @@
 // Code generated from OpenAPI specification. DO NOT EDIT manually.
 package bankaccountactor
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ValidationError describes a value that violates a constraint declared in the OpenAPI schema.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+// nestedValidationError reports a ValidationError of a nested value under the path of the field
+// holding it, e.g. "events[3].amount".
+func nestedValidationError(field string, err error) error {
+	if v, ok := err.(*ValidationError); ok {
+		return &ValidationError{Field: field + "." + v.Field, Message: v.Message}
+	}
+	return err
+}
+
+// AccountEventType Type of event recorded for a bank account
+type AccountEventType string
+
+// Values of AccountEventType declared in the OpenAPI schema
+const (
+	AccountEventTypeAccountCreated AccountEventType = "AccountCreated"
+	AccountEventTypeMoneyDeposited AccountEventType = "MoneyDeposited"
+	AccountEventTypeMoneyWithdrawn AccountEventType = "MoneyWithdrawn"
+)
+
+// String returns the wire representation of AccountEventType.
+func (e AccountEventType) String() string {
+	return string(e)
+}
+
+// IsValid reports whether e is one of the values declared in the OpenAPI schema.
+func (e AccountEventType) IsValid() bool {
+	switch e {
+	case AccountEventTypeAccountCreated, AccountEventTypeMoneyDeposited, AccountEventTypeMoneyWithdrawn:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON rejects values that are not declared in the OpenAPI schema.
+func (e *AccountEventType) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if !AccountEventType(value).IsValid() {
+		return fmt.Errorf("invalid AccountEventType value: %s", data)
+	}
+	*e = AccountEventType(value)
+	return nil
+}
+
+// AccountEventData Event-specific data, selected by the eventType discriminator
+// It is a sealed union implemented by AccountCreatedEventData, MoneyDepositedEventData, MoneyWithdrawnEventData.
+type AccountEventData interface {
+	Validate() error
+	isAccountEventData()
+}
+
+// UnmarshalAccountEventData decodes the AccountEventData variant selected by the "eventType" property.
+func UnmarshalAccountEventData(data []byte) (AccountEventData, error) {
+	var probe struct {
+		Discriminator string `json:"eventType"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+	return UnmarshalAccountEventDataAs(probe.Discriminator, data)
+}
+
+// UnmarshalAccountEventDataAs decodes data as the AccountEventData variant identified by the discriminator value,
+// for payloads that carry the discriminator outside of data.
+func UnmarshalAccountEventDataAs(discriminator string, data []byte) (AccountEventData, error) {
+	switch discriminator {
+	case "AccountCreated":
+		var value AccountCreatedEventData
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		value.EventType = "AccountCreated"
+		return value, nil
+	case "MoneyDeposited":
+		var value MoneyDepositedEventData
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		value.EventType = "MoneyDeposited"
+		return value, nil
+	case "MoneyWithdrawn":
+		var value MoneyWithdrawnEventData
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		value.EventType = "MoneyWithdrawn"
+		return value, nil
+	}
+	return nil, fmt.Errorf("unknown AccountEventData eventType: %q", discriminator)
+}
+
+// AccountAlreadyExistsError An account has already been created for this actor ID
+type AccountAlreadyExistsError struct {
+	// Human-readable error message
+	Message string `json:"message"`
+}
+
+// Validate checks AccountAlreadyExistsError against the constraints declared in the OpenAPI schema.
+func (r AccountAlreadyExistsError) Validate() error {
+	return nil
+}
+
+// ErrorCodeAccountAlreadyExists is the stable code identifying AccountAlreadyExistsError across the actor boundary.
+const ErrorCodeAccountAlreadyExists = "account_already_exists"
+
+// Error implements the error interface, so AccountAlreadyExistsError can be returned from actor methods.
+func (r *AccountAlreadyExistsError) Error() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return ErrorCodeAccountAlreadyExists
+}
+
+// ErrorCode returns the stable code identifying AccountAlreadyExistsError.
+func (r *AccountAlreadyExistsError) ErrorCode() string {
+	return ErrorCodeAccountAlreadyExists
+}
+
+// AccountCreatedEventData Payload of an AccountCreated event
+type AccountCreatedEventData struct {
+	// Account creation timestamp
+	CreatedAt time.Time `json:"createdAt"`
+	// Type of event recorded for a bank account
+	EventType AccountEventType `json:"eventType"`
+	// Initial deposit amount
+	InitialDeposit float64 `json:"initialDeposit"`
+	// Name of the account owner
+	OwnerName string `json:"ownerName"`
+}
+
+// Validate checks AccountCreatedEventData against the constraints declared in the OpenAPI schema.
+func (r AccountCreatedEventData) Validate() error {
+	if !r.EventType.IsValid() {
+		return &ValidationError{Field: "eventType", Message: "must be one of AccountCreated, MoneyDeposited, MoneyWithdrawn"}
+	}
+	return nil
+}
+
+func (AccountCreatedEventData) isAccountEventData() {}
+
+// MarshalJSON always encodes the discriminator value identifying AccountCreatedEventData.
+func (r AccountCreatedEventData) MarshalJSON() ([]byte, error) {
+	type plain AccountCreatedEventData
+	value := plain(r)
+	value.EventType = "AccountCreated"
+	return json.Marshal(value)
+}
+
+// AccountEvent A single account event
+type AccountEvent struct {
+	// Event-specific data, selected by the eventType discriminator
+	Data AccountEventData `json:"data"`
+	// Unique event identifier
+	EventId uuid.UUID `json:"eventId"`
+	// Type of event recorded for a bank account
+	EventType AccountEventType `json:"eventType"`
+	// Idempotency key of the command that stored the event
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
+	// When the event occurred
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Validate checks AccountEvent against the constraints declared in the OpenAPI schema.
+func (r AccountEvent) Validate() error {
+	if r.Data == nil {
+		return &ValidationError{Field: "data", Message: "is required"}
+	}
+	if err := r.Data.Validate(); err != nil {
+		return nestedValidationError("data", err)
+	}
+	if !r.EventType.IsValid() {
+		return &ValidationError{Field: "eventType", Message: "must be one of AccountCreated, MoneyDeposited, MoneyWithdrawn"}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the union fields of AccountEvent by their discriminator.
+func (r *AccountEvent) UnmarshalJSON(data []byte) error {
+	type plain AccountEvent
+	var raw struct {
+		*plain
+		Data json.RawMessage `json:"data"`
+	}
+	raw.plain = (*plain)(r)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.Data = nil
+	if len(raw.Data) > 0 && string(raw.Data) != "null" {
+		value, err := UnmarshalAccountEventData(raw.Data)
+		if err != nil {
+			return err
+		}
+		r.Data = value
+	}
+	return nil
+}
+
+// AccountNotFoundError No account has been created for this actor ID
+type AccountNotFoundError struct {
+	// Human-readable error message
+	Message string `json:"message"`
+}
+
+// Validate checks AccountNotFoundError against the constraints declared in the OpenAPI schema.
+func (r AccountNotFoundError) Validate() error {
+	return nil
+}
+
+// ErrorCodeAccountNotFound is the stable code identifying AccountNotFoundError across the actor boundary.
+const ErrorCodeAccountNotFound = "account_not_found"
+
+// Error implements the error interface, so AccountNotFoundError can be returned from actor methods.
+func (r *AccountNotFoundError) Error() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return ErrorCodeAccountNotFound
+}
+
+// ErrorCode returns the stable code identifying AccountNotFoundError.
+func (r *AccountNotFoundError) ErrorCode() string {
+	return ErrorCodeAccountNotFound
+}
 
 // BankAccountState Current state of bank account (computed from events)
 type BankAccountState struct {
@@ -11,47 +256,252 @@ type BankAccountState struct {
 	// Current account balance (computed from events)
 	Balance float64 `json:"balance"`
 	// Account creation timestamp
-	CreatedAt string `json:"createdAt,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
 	// Whether account is active
 	IsActive bool `json:"isActive"`
 	// Account owner name
 	OwnerName string `json:"ownerName"`
+	// Sequence number of the last event applied to the account; pass it as expectedVersion to make a command conditional
+	Version int64 `json:"version"`
 }
 
-// CounterState Current state of the counter actor (state-based)
-type CounterState struct {
-	// The current counter value
-	Value int32 `json:"value"`
+// Validate checks BankAccountState against the constraints declared in the OpenAPI schema.
+func (r BankAccountState) Validate() error {
+	if r.Version < 0 {
+		return &ValidationError{Field: "version", Message: "must be greater than or equal to 0"}
+	}
+	return nil
 }
 
 // CreateAccountRequest Request to create a new bank account
 type CreateAccountRequest struct {
+	// Create the account only if the account is at this version (0 before it is created)
+	ExpectedVersion *int64 `json:"expectedVersion,omitempty"`
 	// Initial deposit amount
 	InitialDeposit float64 `json:"initialDeposit"`
 	// Name of the account owner
 	OwnerName string `json:"ownerName"`
 }
 
+// Validate checks CreateAccountRequest against the constraints declared in the OpenAPI schema.
+func (r CreateAccountRequest) Validate() error {
+	if r.ExpectedVersion != nil && *r.ExpectedVersion < 0 {
+		return &ValidationError{Field: "expectedVersion", Message: "must be greater than or equal to 0"}
+	}
+	if r.InitialDeposit < 0 {
+		return &ValidationError{Field: "initialDeposit", Message: "must be greater than or equal to 0"}
+	}
+	if r.OwnerName == "" {
+		return &ValidationError{Field: "ownerName", Message: "must not be empty"}
+	}
+	if utf8.RuneCountInString(r.OwnerName) > 100 {
+		return &ValidationError{Field: "ownerName", Message: "must be at most 100 characters"}
+	}
+	return nil
+}
+
 // DepositRequest Request to deposit money
 type DepositRequest struct {
 	// Amount to deposit
 	Amount float64 `json:"amount"`
 	// Description of the deposit
 	Description string `json:"description"`
+	// Deposit only if the account is at this version
+	ExpectedVersion *int64 `json:"expectedVersion,omitempty"`
+	// Key identifying this deposit; a retry with the same key returns the original result without depositing again, and reusing it for a different request fails
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
+}
+
+// Validate checks DepositRequest against the constraints declared in the OpenAPI schema.
+func (r DepositRequest) Validate() error {
+	if r.Amount < 0.01 {
+		return &ValidationError{Field: "amount", Message: "must be greater than or equal to 0.01"}
+	}
+	if utf8.RuneCountInString(r.Description) > 200 {
+		return &ValidationError{Field: "description", Message: "must be at most 200 characters"}
+	}
+	if r.ExpectedVersion != nil && *r.ExpectedVersion < 0 {
+		return &ValidationError{Field: "expectedVersion", Message: "must be greater than or equal to 0"}
+	}
+	if r.IdempotencyKey != nil && *r.IdempotencyKey == "" {
+		return &ValidationError{Field: "idempotencyKey", Message: "must not be empty"}
+	}
+	if r.IdempotencyKey != nil && utf8.RuneCountInString(*r.IdempotencyKey) > 100 {
+		return &ValidationError{Field: "idempotencyKey", Message: "must be at most 100 characters"}
+	}
+	return nil
+}
+
+// IdempotencyKeyConflictError The idempotency key was already used by a request of another method or with other values
+type IdempotencyKeyConflictError struct {
+	// The reused idempotency key
+	IdempotencyKey string `json:"idempotencyKey"`
+	// Human-readable error message
+	Message string `json:"message"`
+}
+
+// Validate checks IdempotencyKeyConflictError against the constraints declared in the OpenAPI schema.
+func (r IdempotencyKeyConflictError) Validate() error {
+	return nil
+}
+
+// ErrorCodeIdempotencyKeyConflict is the stable code identifying IdempotencyKeyConflictError across the actor boundary.
+const ErrorCodeIdempotencyKeyConflict = "idempotency_key_conflict"
+
+// Error implements the error interface, so IdempotencyKeyConflictError can be returned from actor methods.
+func (r *IdempotencyKeyConflictError) Error() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return ErrorCodeIdempotencyKeyConflict
+}
+
+// ErrorCode returns the stable code identifying IdempotencyKeyConflictError.
+func (r *IdempotencyKeyConflictError) ErrorCode() string {
+	return ErrorCodeIdempotencyKeyConflict
+}
+
+// InsufficientFundsError The account balance does not cover the requested withdrawal
+type InsufficientFundsError struct {
+	// Current account balance
+	Balance float64 `json:"balance"`
+	// Human-readable error message
+	Message string `json:"message"`
+	// Amount requested for withdrawal
+	Requested float64 `json:"requested"`
+}
+
+// Validate checks InsufficientFundsError against the constraints declared in the OpenAPI schema.
+func (r InsufficientFundsError) Validate() error {
+	return nil
+}
+
+// ErrorCodeInsufficientFunds is the stable code identifying InsufficientFundsError across the actor boundary.
+const ErrorCodeInsufficientFunds = "insufficient_funds"
+
+// Error implements the error interface, so InsufficientFundsError can be returned from actor methods.
+func (r *InsufficientFundsError) Error() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return ErrorCodeInsufficientFunds
+}
+
+// ErrorCode returns the stable code identifying InsufficientFundsError.
+func (r *InsufficientFundsError) ErrorCode() string {
+	return ErrorCodeInsufficientFunds
+}
+
+// MoneyDepositedEventData Payload of a MoneyDeposited event
+type MoneyDepositedEventData struct {
+	// Amount deposited
+	Amount float64 `json:"amount"`
+	// Description of the deposit
+	Description string `json:"description"`
+	// Type of event recorded for a bank account
+	EventType AccountEventType `json:"eventType"`
+	// When the deposit was made
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Validate checks MoneyDepositedEventData against the constraints declared in the OpenAPI schema.
+func (r MoneyDepositedEventData) Validate() error {
+	if !r.EventType.IsValid() {
+		return &ValidationError{Field: "eventType", Message: "must be one of AccountCreated, MoneyDeposited, MoneyWithdrawn"}
+	}
+	return nil
+}
+
+func (MoneyDepositedEventData) isAccountEventData() {}
+
+// MarshalJSON always encodes the discriminator value identifying MoneyDepositedEventData.
+func (r MoneyDepositedEventData) MarshalJSON() ([]byte, error) {
+	type plain MoneyDepositedEventData
+	value := plain(r)
+	value.EventType = "MoneyDeposited"
+	return json.Marshal(value)
+}
+
+// MoneyWithdrawnEventData Payload of a MoneyWithdrawn event
+type MoneyWithdrawnEventData struct {
+	// Amount withdrawn
+	Amount float64 `json:"amount"`
+	// Description of the withdrawal
+	Description string `json:"description"`
+	// Type of event recorded for a bank account
+	EventType AccountEventType `json:"eventType"`
+	// When the withdrawal was made
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Validate checks MoneyWithdrawnEventData against the constraints declared in the OpenAPI schema.
+func (r MoneyWithdrawnEventData) Validate() error {
+	if !r.EventType.IsValid() {
+		return &ValidationError{Field: "eventType", Message: "must be one of AccountCreated, MoneyDeposited, MoneyWithdrawn"}
+	}
+	return nil
 }
 
-// SetValueRequest Request to set the counter to a specific value
-type SetValueRequest struct {
-	// The value to set the counter to
-	Value int32 `json:"value"`
+func (MoneyWithdrawnEventData) isAccountEventData() {}
+
+// MarshalJSON always encodes the discriminator value identifying MoneyWithdrawnEventData.
+func (r MoneyWithdrawnEventData) MarshalJSON() ([]byte, error) {
+	type plain MoneyWithdrawnEventData
+	value := plain(r)
+	value.EventType = "MoneyWithdrawn"
+	return json.Marshal(value)
 }
 
 // TransactionHistory Complete transaction history (event sourcing benefit)
 type TransactionHistory struct {
-	// List of all events in chronological order
-	Events []interface{} `json:"events"`
 	// Account identifier
 	AccountId string `json:"accountId"`
+	// List of all events in chronological order
+	Events []AccountEvent `json:"events"`
+}
+
+// Validate checks TransactionHistory against the constraints declared in the OpenAPI schema.
+func (r TransactionHistory) Validate() error {
+	if r.Events == nil {
+		return &ValidationError{Field: "events", Message: "is required"}
+	}
+	for i, item := range r.Events {
+		if err := item.Validate(); err != nil {
+			return nestedValidationError(fmt.Sprintf("events[%d]", i), err)
+		}
+	}
+	return nil
+}
+
+// VersionConflictError The account is not at the version the command expected
+type VersionConflictError struct {
+	// Current version of the account
+	CurrentVersion int64 `json:"currentVersion"`
+	// Version the command expected
+	ExpectedVersion int64 `json:"expectedVersion"`
+	// Human-readable error message
+	Message string `json:"message"`
+}
+
+// Validate checks VersionConflictError against the constraints declared in the OpenAPI schema.
+func (r VersionConflictError) Validate() error {
+	return nil
+}
+
+// ErrorCodeVersionConflict is the stable code identifying VersionConflictError across the actor boundary.
+const ErrorCodeVersionConflict = "version_conflict"
+
+// Error implements the error interface, so VersionConflictError can be returned from actor methods.
+func (r *VersionConflictError) Error() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return ErrorCodeVersionConflict
+}
+
+// ErrorCode returns the stable code identifying VersionConflictError.
+func (r *VersionConflictError) ErrorCode() string {
+	return ErrorCodeVersionConflict
 }
 
 // WithdrawRequest Request to withdraw money
@@ -60,17 +510,28 @@ type WithdrawRequest struct {
 	Amount float64 `json:"amount"`
 	// Description of the withdrawal
 	Description string `json:"description"`
+	// Withdraw only if the account is at this version
+	ExpectedVersion *int64 `json:"expectedVersion,omitempty"`
+	// Key identifying this withdrawal; a retry with the same key returns the original result without withdrawing again, and reusing it for a different request fails
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
 }
 
-// AccountEvent A single account event
-type AccountEvent struct {
-	// Unique event identifier
-	EventId string `json:"eventId"`
-	// Type of event
-	EventType string `json:"eventType"`
-	// When the event occurred
-	Timestamp string `json:"timestamp"`
-	// Event-specific data
-	Data map[string]interface{} `json:"data"`
+// Validate checks WithdrawRequest against the constraints declared in the OpenAPI schema.
+func (r WithdrawRequest) Validate() error {
+	if r.Amount < 0.01 {
+		return &ValidationError{Field: "amount", Message: "must be greater than or equal to 0.01"}
+	}
+	if utf8.RuneCountInString(r.Description) > 200 {
+		return &ValidationError{Field: "description", Message: "must be at most 200 characters"}
+	}
+	if r.ExpectedVersion != nil && *r.ExpectedVersion < 0 {
+		return &ValidationError{Field: "expectedVersion", Message: "must be greater than or equal to 0"}
+	}
+	if r.IdempotencyKey != nil && *r.IdempotencyKey == "" {
+		return &ValidationError{Field: "idempotencyKey", Message: "must not be empty"}
+	}
+	if r.IdempotencyKey != nil && utf8.RuneCountInString(*r.IdempotencyKey) > 100 {
+		return &ValidationError{Field: "idempotencyKey", Message: "must be at most 100 characters"}
+	}
+	return nil
 }
-
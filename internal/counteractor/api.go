@@ -6,6 +6,8 @@ package counteractor
 import (
 	"context"
 	"github.com/dapr/go-sdk/actor"
+	"regexp"
+	"unicode/utf8"
 )
 
 // ActorTypeCounterActor is the Dapr actor type identifier for CounterActor
@@ -15,12 +17,38 @@ const ActorTypeCounterActor = "CounterActor"
 // This interface enforces compile-time schema compliance and includes actor.ServerContext for proper Dapr actor implementation.
 type CounterActorAPI interface {
 	actor.ServerContext
+	// Decrement counter by 1
+	Decrement(ctx context.Context) (*CounterState, error)
 	// Get current counter value
 	Get(ctx context.Context) (*CounterState, error)
 	// Increment counter by 1
 	Increment(ctx context.Context) (*CounterState, error)
 	// Set counter to specific value
 	Set(ctx context.Context, request SetValueRequest) (*CounterState, error)
-	// Decrement counter by 1
-	Decrement(ctx context.Context) (*CounterState, error)
-}
\ No newline at end of file
+}
+
+// CounterActorCallbacks declares the reminder and timer callbacks from the OpenAPI schema.
+// They are unexported so Dapr does not expose them as actor methods; reminders and timers reach
+// them through CounterActorServer, which validates their payloads first.
+type CounterActorCallbacks interface {
+	// Resets the counter to the value in the payload
+	onResetReminder(ctx context.Context, payload ResetReminderPayload) error
+	// Increments the counter each time the timer fires
+	onAutoIncrementTimer(ctx context.Context) error
+}
+
+var actorIDPattern = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+// ValidateActorID checks an actor ID against the actorId parameter constraints declared in the OpenAPI schema.
+func ValidateActorID(id string) error {
+	if id == "" {
+		return &ValidationError{Field: "actorId", Message: "must not be empty"}
+	}
+	if utf8.RuneCountInString(id) > 50 {
+		return &ValidationError{Field: "actorId", Message: "must be at most 50 characters"}
+	}
+	if !actorIDPattern.MatchString(id) {
+		return &ValidationError{Field: "actorId", Message: "must match pattern ^[a-zA-Z0-9_-]+$"}
+	}
+	return nil
+}
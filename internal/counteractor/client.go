@@ -0,0 +1,107 @@
+// Package counteractor provides primitives for OpenAPI-based schema validation.
+//
+// Code generated from OpenAPI specification. DO NOT EDIT manually.
+package counteractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dapr/go-sdk/client"
+)
+
+// ActorInvoker is the subset of the Dapr client used by generated actor clients.
+// client.Client satisfies it; tests can supply an HTTP-based or in-memory implementation.
+type ActorInvoker interface {
+	InvokeActor(ctx context.Context, req *client.InvokeActorRequest) (*client.InvokeActorResponse, error)
+}
+
+// CounterActorClient is a typed client for a single CounterActor instance.
+// Its methods mirror CounterActorAPI, so schema changes break callers at compile time.
+type CounterActorClient struct {
+	invoker ActorInvoker
+	actorID string
+}
+
+// NewCounterActorClient creates a client bound to the CounterActor instance identified by actorID.
+// Usage: counteractor.NewCounterActorClient(daprClient, "actor-1")
+func NewCounterActorClient(invoker ActorInvoker, actorID string) *CounterActorClient {
+	return &CounterActorClient{
+		invoker: invoker,
+		actorID: actorID,
+	}
+}
+
+// ActorID returns the ID of the actor instance this client is bound to.
+func (c *CounterActorClient) ActorID() string {
+	return c.actorID
+}
+
+// Decrement counter by 1
+func (c *CounterActorClient) Decrement(ctx context.Context) (*CounterState, error) {
+	var response CounterState
+	if err := c.invoke(ctx, "Decrement", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Get current counter value
+func (c *CounterActorClient) Get(ctx context.Context) (*CounterState, error) {
+	var response CounterState
+	if err := c.invoke(ctx, "Get", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Increment counter by 1
+func (c *CounterActorClient) Increment(ctx context.Context) (*CounterState, error) {
+	var response CounterState
+	if err := c.invoke(ctx, "Increment", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Set counter to specific value
+func (c *CounterActorClient) Set(ctx context.Context, request SetValueRequest) (*CounterState, error) {
+	var response CounterState
+	if err := c.invoke(ctx, "Set", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// invoke marshals the request, calls the actor method through Dapr and unmarshals the response.
+func (c *CounterActorClient) invoke(ctx context.Context, method string, request interface{}, response interface{}) error {
+	req := &client.InvokeActorRequest{
+		ActorType: ActorTypeCounterActor,
+		ActorID:   c.actorID,
+		Method:    method,
+	}
+
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s request: %w", method, err)
+		}
+		req.Data = data
+	}
+
+	resp, err := c.invoker.InvokeActor(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to invoke %s/%s.%s: %w", ActorTypeCounterActor, c.actorID, method, err)
+	}
+
+	if resp == nil || len(resp.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp.Data, response); err != nil {
+		return fmt.Errorf("failed to unmarshal %s response: %w", method, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+// Package counteractor provides primitives for OpenAPI-based schema validation.
+//
+// Code generated from OpenAPI specification. DO NOT EDIT manually.
+package counteractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dapr/go-sdk/actor"
+)
+
+// contractActorID is the actor ID the contract test invokes, derived from the actor type.
+const contractActorID = "counter-actor-contract"
+
+// TestCounterActorContract invokes every method of the actor, in method name order, with the request
+// built from the OpenAPI examples. Each call runs on a newly activated instance that shares the state
+// saved by the previous calls, as after a Dapr actor deactivation. A result must decode into the
+// declared response type and pass its validation; an error must be one of the errors declared for
+// the method.
+func TestCounterActorContract(t *testing.T) {
+	store := make(map[string][]byte)
+
+	t.Run("Decrement", func(t *testing.T) {
+		ctx := context.Background()
+		server := newContractServer(t, store)
+
+		result, err := server.Decrement(ctx)
+		if err != nil {
+			t.Fatalf("Decrement returned an error not declared in the schema: %v", err)
+		}
+		data := saveContractResult(t, server, result)
+
+		var response CounterState
+		decodeContractResult(t, data, &response)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		ctx := context.Background()
+		server := newContractServer(t, store)
+
+		result, err := server.Get(ctx)
+		if err != nil {
+			t.Fatalf("Get returned an error not declared in the schema: %v", err)
+		}
+		data := saveContractResult(t, server, result)
+
+		var response CounterState
+		decodeContractResult(t, data, &response)
+	})
+
+	t.Run("Increment", func(t *testing.T) {
+		ctx := context.Background()
+		server := newContractServer(t, store)
+
+		result, err := server.Increment(ctx)
+		if err != nil {
+			t.Fatalf("Increment returned an error not declared in the schema: %v", err)
+		}
+		data := saveContractResult(t, server, result)
+
+		var response CounterState
+		decodeContractResult(t, data, &response)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		ctx := context.Background()
+		server := newContractServer(t, store)
+
+		var request SetValueRequest
+		decodeContractJSON(t, `{"value":100}`, &request)
+		result, err := server.Set(ctx, request)
+		if err != nil {
+			t.Fatalf("Set returned an error not declared in the schema: %v", err)
+		}
+		data := saveContractResult(t, server, result)
+
+		var response CounterState
+		decodeContractResult(t, data, &response)
+	})
+
+}
+
+// newContractServer activates an actor instance through NewActorFactory with its state in store.
+func newContractServer(t *testing.T, store map[string][]byte) *CounterActorServer {
+	t.Helper()
+	server, ok := NewActorFactory()().(*CounterActorServer)
+	if !ok {
+		t.Fatal("NewActorFactory does not return a *CounterActorServer")
+	}
+	server.SetID(contractActorID)
+	server.SetStateManager(newMemoryStateManager(store))
+	return server
+}
+
+// saveContractResult encodes a method result as Dapr returns it to the caller and then saves the
+// actor state, as Dapr does after every successful call.
+func saveContractResult(t *testing.T, server *CounterActorServer, result interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	if err := server.SaveState(context.Background()); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+	return data
+}
+
+// decodeContractJSON decodes data, rejecting properties the schema does not declare.
+func decodeContractJSON(t *testing.T, data string, v interface{}) {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		t.Fatalf("%s does not match the schema: %v", data, err)
+	}
+}
+
+// decodeContractResult decodes a result into its response type and validates it against the schema.
+func decodeContractResult(t *testing.T, data []byte, v interface{}) {
+	t.Helper()
+	if bytes.Equal(data, []byte("null")) {
+		t.Fatal("method returned no result")
+	}
+	decodeContractJSON(t, string(data), v)
+	if validator, ok := v.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			t.Fatalf("result %s is invalid: %v", data, err)
+		}
+	}
+}
+
+// memoryStateManager is an in-memory actor.StateManagerContext. Values are stored JSON-encoded, as
+// in a Dapr state store, and changes become visible to other instances when they are saved.
+type memoryStateManager struct {
+	store   map[string][]byte
+	changes map[string][]byte // Pending changes; nil marks a removed key
+}
+
+var _ actor.StateManagerContext = (*memoryStateManager)(nil)
+
+func newMemoryStateManager(store map[string][]byte) *memoryStateManager {
+	return &memoryStateManager{store: store, changes: make(map[string][]byte)}
+}
+
+func (m *memoryStateManager) lookup(stateName string) ([]byte, bool) {
+	if value, ok := m.changes[stateName]; ok {
+		return value, value != nil
+	}
+	value, ok := m.store[stateName]
+	return value, ok
+}
+
+func (m *memoryStateManager) Add(ctx context.Context, stateName string, value any) error {
+	if _, ok := m.lookup(stateName); ok {
+		return fmt.Errorf("duplicate state: %s", stateName)
+	}
+	return m.Set(ctx, stateName, value)
+}
+
+func (m *memoryStateManager) Get(_ context.Context, stateName string, reply any) error {
+	value, ok := m.lookup(stateName)
+	if !ok {
+		return fmt.Errorf("state not found: %s", stateName)
+	}
+	return json.Unmarshal(value, reply)
+}
+
+func (m *memoryStateManager) Set(_ context.Context, stateName string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode state %s: %v", stateName, err)
+	}
+	m.changes[stateName] = data
+	return nil
+}
+
+func (m *memoryStateManager) SetWithTTL(ctx context.Context, stateName string, value any, _ time.Duration) error {
+	return m.Set(ctx, stateName, value)
+}
+
+func (m *memoryStateManager) Remove(_ context.Context, stateName string) error {
+	m.changes[stateName] = nil
+	return nil
+}
+
+func (m *memoryStateManager) Contains(_ context.Context, stateName string) (bool, error) {
+	_, ok := m.lookup(stateName)
+	return ok, nil
+}
+
+func (m *memoryStateManager) Save(ctx context.Context) error {
+	for stateName, value := range m.changes {
+		if value == nil {
+			delete(m.store, stateName)
+		} else {
+			m.store[stateName] = value
+		}
+	}
+	m.Flush(ctx)
+	return nil
+}
+
+func (m *memoryStateManager) Flush(context.Context) {
+	m.changes = make(map[string][]byte)
+}
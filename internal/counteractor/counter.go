@@ -2,7 +2,6 @@ package counteractor
 
 import (
 	"context"
-	"errors"
 	
 	"github.com/dapr/go-sdk/actor"
 )
@@ -12,8 +11,9 @@ import (
 // CounterActor demonstrates schema-first development using generated OpenAPI types.
 // It implements the generated CounterActorAPI interface to ensure compile-time schema compliance.
 //
-// Note: Dapr actors return errors as strings through the HTTP layer, so custom error types
-// with structured data cannot be returned directly. Use standard Go errors for actor methods.
+// Note: Dapr reports actor method errors to callers as a bare failure. Errors that callers need
+// to handle should be declared as 4xx/5xx response schemas, which the generator turns into typed
+// errors carried to the generated client (see BankAccountActor). Other errors are plain Go errors.
 type CounterActor struct {
 	actor.ServerImplBaseCtx
 }
@@ -61,11 +61,9 @@ func (c *CounterActor) Get(ctx context.Context) (*CounterState, error) {
 	return state, nil
 }
 
+// Set stores the requested value. Request validation is performed by the generated
+// CounterActorServer wrapper before this method is called.
 func (c *CounterActor) Set(ctx context.Context, request SetValueRequest) (*CounterState, error) {
-	if err := c.validateSetRequest(request); err != nil {
-		return nil, err
-	}
-	
 	state := &CounterState{Value: request.Value}
 	
 	if err := c.setState(ctx, state); err != nil {
@@ -75,6 +73,18 @@ func (c *CounterActor) Set(ctx context.Context, request SetValueRequest) (*Count
 	return state, nil
 }
 
+// onResetReminder sets the counter to the value carried by the reset reminder. The generated
+// ReminderCall dispatcher validates the payload and saves the state afterwards.
+func (c *CounterActor) onResetReminder(ctx context.Context, payload ResetReminderPayload) error {
+	return c.setState(ctx, &CounterState{Value: payload.Value})
+}
+
+// onAutoIncrementTimer increments the counter each time the autoIncrement timer fires.
+func (c *CounterActor) onAutoIncrementTimer(ctx context.Context) error {
+	_, err := c.Increment(ctx)
+	return err
+}
+
 func (c *CounterActor) getState(ctx context.Context) (*CounterState, error) {
 	stateKey := "counter"
 	var state CounterState
@@ -99,17 +109,4 @@ func (c *CounterActor) getState(ctx context.Context) (*CounterState, error) {
 func (c *CounterActor) setState(ctx context.Context, state *CounterState) error {
 	stateKey := "counter"
 	return c.GetStateManager().Set(ctx, stateKey, state)
-}
-
-func (c *CounterActor) validateSetRequest(request SetValueRequest) error {
-	const (
-		minInt32 = -2147483648
-		maxInt32 = 2147483647
-	)
-	
-	if request.Value < minInt32 || request.Value > maxInt32 {
-		return errors.New("value out of range for int32")
-	}
-	
-	return nil
 }
\ No newline at end of file
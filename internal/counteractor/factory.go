@@ -4,26 +4,76 @@
 package counteractor
 
 import (
+	"context"
 	"fmt"
 	"github.com/dapr/go-sdk/actor"
 )
 
 // NewActorFactory creates a factory function for CounterActor with a cleaner API.
 // Returns a factory function compatible with Dapr's RegisterActorImplFactoryContext.
+// Options configure each new CounterActor instance before it is wrapped.
 // Usage: s.RegisterActorImplFactoryContext(counteractor.NewActorFactory())
-func NewActorFactory() func() actor.ServerContext {
+func NewActorFactory(options ...func(*CounterActor)) func() actor.ServerContext {
 	return func() actor.ServerContext {
 		// Create a new CounterActor instance
 		impl := &CounterActor{}
-		
+		for _, option := range options {
+			option(impl)
+		}
+
 		// Compile-time check ensures the implementation satisfies the schema
 		var _ CounterActorAPI = impl
-		
+		var _ CounterActorCallbacks = impl
+
 		// Verify the actor type matches the schema
 		if impl.Type() != ActorTypeCounterActor {
 			panic(fmt.Sprintf("actor implementation Type() returns '%s', expected '%s'", impl.Type(), ActorTypeCounterActor))
 		}
-		
-		return impl
+
+		// Wrap the implementation so invalid input is rejected before it runs
+		return &CounterActorServer{CounterActorAPI: impl, callbacks: impl}
+	}
+}
+
+// CounterActorServer wraps a CounterActorAPI implementation and validates the actor ID
+// and request bodies against the OpenAPI schema before delegating to the implementation.
+type CounterActorServer struct {
+	CounterActorAPI
+
+	callbacks CounterActorCallbacks // Reminder and timer callbacks, which are not actor methods
+}
+
+// Decrement validates the input and delegates to the implementation.
+func (s *CounterActorServer) Decrement(ctx context.Context) (*CounterState, error) {
+	if err := ValidateActorID(s.ID()); err != nil {
+		return nil, err
+	}
+	return s.CounterActorAPI.Decrement(ctx)
+}
+
+// Get validates the input and delegates to the implementation.
+func (s *CounterActorServer) Get(ctx context.Context) (*CounterState, error) {
+	if err := ValidateActorID(s.ID()); err != nil {
+		return nil, err
+	}
+	return s.CounterActorAPI.Get(ctx)
+}
+
+// Increment validates the input and delegates to the implementation.
+func (s *CounterActorServer) Increment(ctx context.Context) (*CounterState, error) {
+	if err := ValidateActorID(s.ID()); err != nil {
+		return nil, err
+	}
+	return s.CounterActorAPI.Increment(ctx)
+}
+
+// Set validates the input and delegates to the implementation.
+func (s *CounterActorServer) Set(ctx context.Context, request SetValueRequest) (*CounterState, error) {
+	if err := ValidateActorID(s.ID()); err != nil {
+		return nil, err
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
 	}
-}
\ No newline at end of file
+	return s.CounterActorAPI.Set(ctx, request)
+}
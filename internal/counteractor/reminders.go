@@ -0,0 +1,161 @@
+// Package counteractor provides primitives for OpenAPI-based schema validation.
+//
+// Code generated from OpenAPI specification. DO NOT EDIT manually.
+package counteractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/dapr/go-sdk/client"
+)
+
+// Reminder and timer names declared in the OpenAPI schema for CounterActor
+const (
+	ReminderReset      = "reset"
+	TimerAutoIncrement = "autoIncrement"
+)
+
+// Schedule sets when a reminder or timer fires, in Dapr's duration or ISO 8601 format
+// (e.g. "10s", "PT1H", "R5/PT1M"). Empty fields fall back to the defaults declared in the schema.
+type Schedule struct {
+	DueTime string
+	Period  string
+	TTL     string
+}
+
+// withDefaults fills the empty fields of s from defaults.
+func (s Schedule) withDefaults(defaults Schedule) Schedule {
+	if s.DueTime == "" {
+		s.DueTime = defaults.DueTime
+	}
+	if s.Period == "" {
+		s.Period = defaults.Period
+	}
+	if s.TTL == "" {
+		s.TTL = defaults.TTL
+	}
+	return s
+}
+
+// ActorScheduler is the subset of the Dapr client used to register reminders and timers.
+// client.Client satisfies it.
+type ActorScheduler interface {
+	RegisterActorReminder(ctx context.Context, req *client.RegisterActorReminderRequest) error
+	UnregisterActorReminder(ctx context.Context, req *client.UnregisterActorReminderRequest) error
+	RegisterActorTimer(ctx context.Context, req *client.RegisterActorTimerRequest) error
+	UnregisterActorTimer(ctx context.Context, req *client.UnregisterActorTimerRequest) error
+}
+
+// CounterActorScheduler registers the reminders and timers of a single CounterActor instance.
+// Usage: counteractor.NewCounterActorScheduler(daprClient, "actor-1")
+type CounterActorScheduler struct {
+	scheduler ActorScheduler
+	actorID   string
+}
+
+// NewCounterActorScheduler creates a scheduler bound to the CounterActor instance identified by actorID.
+func NewCounterActorScheduler(scheduler ActorScheduler, actorID string) *CounterActorScheduler {
+	return &CounterActorScheduler{
+		scheduler: scheduler,
+		actorID:   actorID,
+	}
+}
+
+// RegisterResetReminder registers the reset reminder, which Dapr delivers to ReminderCall
+// and ReminderCall passes to the onResetReminder callback.
+func (s *CounterActorScheduler) RegisterResetReminder(ctx context.Context, payload ResetReminderPayload, schedule Schedule) error {
+	schedule = schedule.withDefaults(Schedule{DueTime: "", Period: "", TTL: ""})
+	req := &client.RegisterActorReminderRequest{
+		ActorType: ActorTypeCounterActor,
+		ActorID:   s.actorID,
+		Name:      ReminderReset,
+		DueTime:   schedule.DueTime,
+		Period:    schedule.Period,
+		TTL:       schedule.TTL,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s payload: %w", ReminderReset, err)
+	}
+	req.Data = data
+
+	return s.scheduler.RegisterActorReminder(ctx, req)
+}
+
+// UnregisterResetReminder removes the reset reminder.
+func (s *CounterActorScheduler) UnregisterResetReminder(ctx context.Context) error {
+	return s.scheduler.UnregisterActorReminder(ctx, &client.UnregisterActorReminderRequest{
+		ActorType: ActorTypeCounterActor,
+		ActorID:   s.actorID,
+		Name:      ReminderReset,
+	})
+}
+
+// RegisterAutoIncrementTimer registers the autoIncrement timer, which Dapr fires by invoking OnAutoIncrementTimer.
+func (s *CounterActorScheduler) RegisterAutoIncrementTimer(ctx context.Context, schedule Schedule) error {
+	schedule = schedule.withDefaults(Schedule{DueTime: "10s", Period: "10s", TTL: ""})
+	req := &client.RegisterActorTimerRequest{
+		ActorType: ActorTypeCounterActor,
+		ActorID:   s.actorID,
+		Name:      TimerAutoIncrement,
+		DueTime:   schedule.DueTime,
+		Period:    schedule.Period,
+		TTL:       schedule.TTL,
+		CallBack:  "OnAutoIncrementTimer",
+	}
+
+	return s.scheduler.RegisterActorTimer(ctx, req)
+}
+
+// UnregisterAutoIncrementTimer stops the autoIncrement timer.
+func (s *CounterActorScheduler) UnregisterAutoIncrementTimer(ctx context.Context) error {
+	return s.scheduler.UnregisterActorTimer(ctx, &client.UnregisterActorTimerRequest{
+		ActorType: ActorTypeCounterActor,
+		ActorID:   s.actorID,
+		Name:      TimerAutoIncrement,
+	})
+}
+
+// ReminderCall dispatches the reminders declared in the OpenAPI schema to their typed callbacks
+// and saves the state they changed, which Dapr does not do after reminders.
+// Failures are logged because Dapr gives reminders no way to report them.
+func (s *CounterActorServer) ReminderCall(reminderName string, state []byte, dueTime string, period string) {
+	ctx := context.Background()
+
+	var err error
+	switch reminderName {
+	case ReminderReset:
+		var payload ResetReminderPayload
+		if err = json.Unmarshal(state, &payload); err != nil {
+			break
+		}
+		if err = payload.Validate(); err != nil {
+			break
+		}
+		err = s.callbacks.onResetReminder(ctx, payload)
+	default:
+		err = fmt.Errorf("unknown reminder %q", reminderName)
+	}
+
+	if err == nil {
+		err = s.SaveState(ctx)
+	}
+	if err != nil {
+		log.Printf("%s/%s: reminder %s failed: %v", ActorTypeCounterActor, s.ID(), reminderName, err)
+	}
+}
+
+// OnAutoIncrementTimer is invoked by Dapr for the autoIncrement timer. It runs the
+// onAutoIncrementTimer callback and saves the state it changed, which Dapr does not do after timers.
+// Dapr fires timers by invoking an actor method, so OnAutoIncrementTimer is also invocable by any client
+// like the methods of the schema, and the callback must be safe to run at any time.
+func (s *CounterActorServer) OnAutoIncrementTimer(ctx context.Context) error {
+	if err := s.callbacks.onAutoIncrementTimer(ctx); err != nil {
+		return err
+	}
+	return s.SaveState(ctx)
+}
@@ -0,0 +1,62 @@
+// Package registry registers every actor type declared in the OpenAPI specification.
+//
+// Code generated from OpenAPI specification. DO NOT EDIT manually.
+package registry
+
+import (
+	"github.com/dapr/go-sdk/actor"
+	"github.com/dapr/go-sdk/service/common"
+
+	"github.com/shogotsuneto/dapr-actor-experiment/internal/bankaccountactor"
+	"github.com/shogotsuneto/dapr-actor-experiment/internal/counteractor"
+)
+
+// ActorMetadata describes an actor type declared in the OpenAPI specification.
+type ActorMetadata struct {
+	ActorType   string   `json:"actorType"`
+	Description string   `json:"description,omitempty"` // Description of the actor's tag
+	Persistence string   `json:"persistence,omitempty"` // x-dapr-persistence of the actor's tag
+	Methods     []string `json:"methods"`
+
+	// Factory creates the schema-validating server for the actor implementation.
+	Factory func() actor.ServerContext `json:"-"`
+}
+
+// Actors lists the actor types in the order RegisterAll registers them.
+var Actors = []ActorMetadata{
+	{
+		ActorType:   bankaccountactor.ActorTypeBankAccountActor,
+		Description: "Event-sourced bank account with full audit trail",
+		Persistence: "Event-sourced - stores events and computes state",
+		Methods:     []string{"CreateAccount", "Deposit", "GetBalance", "GetHistory", "Withdraw"},
+		Factory:     bankaccountactor.NewActorFactory(),
+	},
+	{
+		ActorType:   counteractor.ActorTypeCounterActor,
+		Description: "State-based counter operations",
+		Persistence: "State-based - stores current value only",
+		Methods:     []string{"Decrement", "Get", "Increment", "Set"},
+		Factory:     counteractor.NewActorFactory(),
+	},
+}
+
+// RegisterAll registers the factories of all actor types with the Dapr service. A factory in
+// factories replaces the default one of its actor type, e.g. to create instances with options.
+func RegisterAll(s common.Service, factories map[string]func() actor.ServerContext) {
+	for _, a := range Actors {
+		factory := a.Factory
+		if f, ok := factories[a.ActorType]; ok {
+			factory = f
+		}
+		s.RegisterActorImplFactoryContext(factory)
+	}
+}
+
+// ActorTypes returns the registered actor type names.
+func ActorTypes() []string {
+	types := make([]string, 0, len(Actors))
+	for _, a := range Actors {
+		types = append(types, a.ActorType)
+	}
+	return types
+}
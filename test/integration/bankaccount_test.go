@@ -2,7 +2,9 @@ package integration
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,6 +34,22 @@ func TestBankAccountActor(t *testing.T) {
 	t.Run("TestBankAccountActorEventSourcing", func(t *testing.T) {
 		testBankAccountActorEventSourcing(t, daprClient)
 	})
+
+	t.Run("TestBankAccountActorSchemaValidation", func(t *testing.T) {
+		testBankAccountActorSchemaValidation(t, daprClient)
+	})
+
+	t.Run("TestBankAccountActorTypedErrors", func(t *testing.T) {
+		testBankAccountActorTypedErrors(t, daprClient)
+	})
+
+	t.Run("TestBankAccountActorExpectedVersion", func(t *testing.T) {
+		testBankAccountActorExpectedVersion(t, daprClient)
+	})
+
+	t.Run("TestBankAccountActorIdempotency", func(t *testing.T) {
+		testBankAccountActorIdempotency(t, daprClient)
+	})
 }
 
 func testBankAccountActorBasicOperations(t *testing.T, client *DaprClient) {
@@ -278,23 +296,19 @@ func testBankAccountActorEventSourcing(t *testing.T, client *DaprClient) {
 	foundDeposits := 0
 	foundWithdrawals := 0
 	foundAccountCreated := 0
-	for _, eventInterface := range history.Events {
-		// Convert interface{} to map[string]interface{} (JSON unmarshaling result)
-		eventMap, ok := eventInterface.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		eventType, ok := eventMap["eventType"].(string)
-		if !ok {
-			continue
-		}
-		switch eventType {
-		case "AccountCreated":
+	for _, event := range history.Events {
+		switch event.EventType {
+		case bankaccountactor.AccountEventTypeAccountCreated:
 			foundAccountCreated++
-		case "MoneyDeposited":
+			assert.IsType(t, bankaccountactor.AccountCreatedEventData{}, event.Data, "AccountCreated event should carry typed data")
+		case bankaccountactor.AccountEventTypeMoneyDeposited:
 			foundDeposits++
-		case "MoneyWithdrawn":
+			deposit, ok := event.Data.(bankaccountactor.MoneyDepositedEventData)
+			require.True(t, ok, "MoneyDeposited event should carry typed data")
+			assert.Greater(t, deposit.Amount, 0.0)
+		case bankaccountactor.AccountEventTypeMoneyWithdrawn:
 			foundWithdrawals++
+			assert.IsType(t, bankaccountactor.MoneyWithdrawnEventData{}, event.Data, "MoneyWithdrawn event should carry typed data")
 		}
 	}
 	assert.GreaterOrEqual(t, foundAccountCreated, 1, "Should have account creation event")
@@ -313,6 +327,144 @@ func testBankAccountActorEventSourcing(t *testing.T, client *DaprClient) {
 	assert.Equal(t, expectedBalance, balance.Balance, "Final balance should match event sourcing calculation")
 }
 
+func testBankAccountActorSchemaValidation(t *testing.T, client *DaprClient) {
+	ctx := context.Background()
+	actorID := "account-schema-validation-test"
+
+	// Requests violating schema constraints are rejected by the generated server wrapper
+	var result interface{}
+	err := client.InvokeActorMethodWithResponse(ctx, ActorMethodRequest{
+		ActorType: "BankAccountActor",
+		ActorID:   actorID,
+		Method:    "CreateAccount",
+		Data: bankaccountactor.CreateAccountRequest{
+			OwnerName:      "",
+			InitialDeposit: 100.0,
+		},
+	}, &result)
+	assert.Error(t, err, "Empty owner name should be rejected (minLength: 1)")
+
+	err = client.InvokeActorMethodWithResponse(ctx, ActorMethodRequest{
+		ActorType: "BankAccountActor",
+		ActorID:   actorID,
+		Method:    "CreateAccount",
+		Data: bankaccountactor.CreateAccountRequest{
+			OwnerName:      "Validation Test",
+			InitialDeposit: 100.0,
+		},
+	}, &result)
+	require.NoError(t, err)
+
+	err = client.InvokeActorMethodWithResponse(ctx, ActorMethodRequest{
+		ActorType: "BankAccountActor",
+		ActorID:   actorID,
+		Method:    "Deposit",
+		Data: bankaccountactor.DepositRequest{
+			Amount:      0,
+			Description: "Zero deposit",
+		},
+	}, &result)
+	assert.Error(t, err, "Zero deposit should be rejected (minimum: 0.01)")
+
+	// Rejected requests must not change the balance
+	var balance bankaccountactor.BankAccountState
+	err = client.InvokeActorMethodWithResponse(ctx, ActorMethodRequest{
+		ActorType: "BankAccountActor",
+		ActorID:   actorID,
+		Method:    "GetBalance",
+	}, &balance)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, balance.Balance, "Balance should be unchanged by rejected requests")
+}
+
+func testBankAccountActorTypedErrors(t *testing.T, client *DaprClient) {
+	ctx := context.Background()
+
+	// Errors declared in the schema reach the generated client as typed values
+	account := bankaccountactor.NewBankAccountActorClient(client, fmt.Sprintf("account-typed-errors-%d", time.Now().UnixNano()))
+
+	_, err := account.GetBalance(ctx)
+	var notFound *bankaccountactor.AccountNotFoundError
+	require.ErrorAs(t, err, &notFound)
+
+	_, err = account.CreateAccount(ctx, bankaccountactor.CreateAccountRequest{OwnerName: "Typed Errors", InitialDeposit: 40.0})
+	require.NoError(t, err)
+
+	_, err = account.CreateAccount(ctx, bankaccountactor.CreateAccountRequest{OwnerName: "Typed Errors", InitialDeposit: 40.0})
+	var exists *bankaccountactor.AccountAlreadyExistsError
+	require.ErrorAs(t, err, &exists)
+	assert.Equal(t, bankaccountactor.ErrorCodeAccountAlreadyExists, exists.ErrorCode())
+
+	_, err = account.Withdraw(ctx, bankaccountactor.WithdrawRequest{Amount: 50.0, Description: "Overdraft"})
+	var insufficient *bankaccountactor.InsufficientFundsError
+	require.ErrorAs(t, err, &insufficient)
+	assert.Equal(t, 40.0, insufficient.Balance)
+	assert.Equal(t, 50.0, insufficient.Requested)
+
+	// The rejected withdrawal must not change the balance
+	state, err := account.GetBalance(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, state.Balance)
+}
+
+func testBankAccountActorExpectedVersion(t *testing.T, client *DaprClient) {
+	ctx := context.Background()
+	account := bankaccountactor.NewBankAccountActorClient(client, fmt.Sprintf("account-version-%d", time.Now().UnixNano()))
+
+	// Every event advances the version returned with the state
+	created, err := account.CreateAccount(ctx, bankaccountactor.CreateAccountRequest{OwnerName: "Versioned", InitialDeposit: 100.0, ExpectedVersion: int64Ptr(0)})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.Version)
+
+	deposited, err := account.Deposit(ctx, bankaccountactor.DepositRequest{Amount: 50.0, Description: "Conditional deposit", ExpectedVersion: &created.Version})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deposited.Version)
+
+	// A command based on a stale read is rejected with the current version
+	_, err = account.Withdraw(ctx, bankaccountactor.WithdrawRequest{Amount: 30.0, Description: "Stale withdrawal", ExpectedVersion: &created.Version})
+	var conflict *bankaccountactor.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, int64(1), conflict.ExpectedVersion)
+	assert.Equal(t, int64(2), conflict.CurrentVersion)
+
+	state, err := account.GetBalance(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 150.0, state.Balance, "The rejected withdrawal must not change the balance")
+	assert.Equal(t, int64(2), state.Version)
+}
+
+func testBankAccountActorIdempotency(t *testing.T, client *DaprClient) {
+	ctx := context.Background()
+	account := bankaccountactor.NewBankAccountActorClient(client, fmt.Sprintf("account-idempotency-%d", time.Now().UnixNano()))
+
+	_, err := account.CreateAccount(ctx, bankaccountactor.CreateAccountRequest{OwnerName: "Idempotent", InitialDeposit: 100.0})
+	require.NoError(t, err)
+
+	// A retried deposit returns the original result and is applied once
+	key := "deposit-retry-1"
+	deposit := bankaccountactor.DepositRequest{Amount: 25.0, Description: "Retried deposit", IdempotencyKey: &key}
+	first, err := account.Deposit(ctx, deposit)
+	require.NoError(t, err)
+	retried, err := account.Deposit(ctx, deposit)
+	require.NoError(t, err)
+	assert.Equal(t, first, retried, "A retry should return the original result")
+
+	state, err := account.GetBalance(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 125.0, state.Balance, "The deposit should be applied once")
+
+	// The key is recorded on the stored event
+	history, err := account.GetHistory(ctx)
+	require.NoError(t, err)
+	require.Len(t, history.Events, 2)
+	require.NotNil(t, history.Events[1].IdempotencyKey)
+	assert.Equal(t, key, *history.Events[1].IdempotencyKey)
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
 // Operation represents a bank account operation
 type Operation struct {
 	Type        string
@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+
+	"github.com/dapr/go-sdk/client"
 )
 
 // GetDaprEndpoint returns the Dapr HTTP endpoint URL, configurable via environment variable
@@ -120,6 +122,31 @@ func (c *DaprClient) InvokeActorMethodWithResponse(ctx context.Context, req Acto
 	return nil
 }
 
+// InvokeActor implements the generated ActorInvoker interface over the Dapr HTTP API,
+// so generated typed actor clients can be used in integration tests.
+func (c *DaprClient) InvokeActor(ctx context.Context, req *client.InvokeActorRequest) (*client.InvokeActorResponse, error) {
+	var data interface{}
+	if req.Data != nil {
+		data = json.RawMessage(req.Data)
+	}
+
+	resp, err := c.InvokeActorMethod(ctx, ActorMethodRequest{
+		ActorType: req.ActorType,
+		ActorID:   req.ActorID,
+		Method:    req.Method,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("actor method returned status %d: %s", resp.StatusCode, string(resp.Body))
+	}
+
+	return &client.InvokeActorResponse{Data: resp.Body}, nil
+}
+
 // CheckHealth verifies that Dapr services are available
 func (c *DaprClient) CheckHealth() error {
 	// Check Dapr sidecar health
@@ -32,6 +32,10 @@ func TestCounterActor(t *testing.T) {
 	t.Run("TestCounterActorMultipleInstances", func(t *testing.T) {
 		testCounterActorMultipleInstances(t, daprClient)
 	})
+
+	t.Run("TestCounterActorTypedClient", func(t *testing.T) {
+		testCounterActorTypedClient(t, daprClient)
+	})
 }
 
 func testCounterActorBasicOperations(t *testing.T, client *DaprClient) {
@@ -99,6 +103,29 @@ func testCounterActorBasicOperations(t *testing.T, client *DaprClient) {
 	assert.Equal(t, int32(9), finalState.Value, "Final counter value should be 9")
 }
 
+func testCounterActorTypedClient(t *testing.T, client *DaprClient) {
+	ctx := context.Background()
+
+	// Generated client invokes the same actor methods with typed requests and responses
+	counter := counteractor.NewCounterActorClient(client, "counter-test-typed-client")
+
+	state, err := counter.Set(ctx, counteractor.SetValueRequest{Value: 7})
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), state.Value, "Counter should be 7 after set")
+
+	state, err = counter.Increment(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(8), state.Value, "Counter should be 8 after increment")
+
+	state, err = counter.Decrement(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), state.Value, "Counter should be 7 after decrement")
+
+	state, err = counter.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), state.Value, "Final counter value should be 7")
+}
+
 func testCounterActorStateIsolation(t *testing.T, client *DaprClient) {
 	ctx := context.Background()
 